@@ -0,0 +1,255 @@
+package util
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrBrokerClosed is reported to subscribers when the broker is closed. It wraps ErrChClosed.
+	ErrBrokerClosed = fmt.Errorf("broker closed: %w", ErrChClosed)
+	// ErrSubscriberEvicted is reported to a subscriber removed by OverflowEvict. It wraps ErrChClosed.
+	ErrSubscriberEvicted = fmt.Errorf("subscriber evicted: %w", ErrChClosed)
+)
+
+// Message is a value published to a topic.
+type Message[T any] struct {
+	Topic string
+	Value T
+}
+
+// Broker is an in-process publish/subscribe hub.
+//
+// Topics are dot-separated, such as "orders.created".
+// Subscription patterns may use "*" to match exactly one segment and "#" to match zero or more segments,
+// e.g. "orders.*" or "orders.#".
+type Broker[T any] struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription[T]]struct{}
+	nextID uint64
+	closed bool
+}
+
+// NewBroker returns an empty Broker.
+func NewBroker[T any]() *Broker[T] {
+	return &Broker[T]{subs: map[*Subscription[T]]struct{}{}}
+}
+
+// Subscription receives the messages published to topics matching its pattern.
+type Subscription[T any] struct {
+	id      uint64
+	broker  *Broker[T]
+	pattern string
+	policy  OverflowPolicy
+	ch      chan Message[T]
+	done    chan struct{}
+	once    sync.Once
+	// mu serializes sends with closing the channel.
+	mu sync.Mutex
+	// err is written with both mu and errMu held, so that Err does not wait for a blocked send.
+	errMu sync.Mutex
+	err   error
+}
+
+// Subscribe subscribes to the topics matching pattern, with the buffer and overflow policy of sub.
+// The subscription ends when ctx is done, when Unsubscribe is called, or when the broker is closed.
+// If the broker is already closed, the returned subscription has already ended with ErrBrokerClosed.
+func (b *Broker[T]) Subscribe(ctx context.Context, pattern string, sub Subscriber) *Subscription[T] {
+	s := &Subscription[T]{
+		broker:  b,
+		pattern: pattern,
+		policy:  sub.Policy,
+		ch:      make(chan Message[T], sub.Buffer),
+		done:    make(chan struct{}),
+	}
+	b.mu.Lock()
+	closed := b.closed
+	if !closed {
+		b.nextID++
+		s.id = b.nextID
+		b.subs[s] = struct{}{}
+	}
+	b.mu.Unlock()
+	if closed {
+		s.end(ErrBrokerClosed)
+		return s
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.end(ctx.Err())
+		case <-s.done:
+		}
+	}()
+	return s
+}
+
+// Publish sends v to every subscription whose pattern matches topic, in subscription order, and returns the number of subscriptions that received it.
+// Subscriptions with OverflowBlock are waited for until ctx is done.
+// If the broker is closed, it returns ErrBrokerClosed; if ctx is done, it returns the context's error.
+func (b *Broker[T]) Publish(ctx context.Context, topic string, v T) (int, error) {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return 0, ErrBrokerClosed
+	}
+	var targets []*Subscription[T]
+	for s := range b.subs {
+		if MatchTopic(s.pattern, topic) {
+			targets = append(targets, s)
+		}
+	}
+	b.mu.RUnlock()
+	slices.SortFunc(targets, func(x, y *Subscription[T]) int { return cmp.Compare(x.id, y.id) })
+
+	msg := Message[T]{Topic: topic, Value: v}
+	delivered := 0
+	for _, s := range targets {
+		ok, err := s.send(ctx, msg)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// Close ends all subscriptions with ErrBrokerClosed. Later publishes fail with ErrBrokerClosed.
+func (b *Broker[T]) Close() {
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subs
+	b.subs = map[*Subscription[T]]struct{}{}
+	b.mu.Unlock()
+	for s := range subs {
+		s.end(ErrBrokerClosed)
+	}
+}
+
+func (s *Subscription[T]) send(ctx context.Context, msg Message[T]) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, nil
+	}
+	switch s.policy {
+	case OverflowDrop:
+		return TrySend(s.ch, msg) == nil, nil
+	case OverflowEvict:
+		if TrySend(s.ch, msg) != nil {
+			s.close(ErrSubscriberEvicted)
+			return false, nil
+		}
+		return true, nil
+	default:
+		select {
+		case s.ch <- msg:
+			return true, nil
+		case <-s.done:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// end removes the subscription from the broker and closes its channel with err.
+func (s *Subscription[T]) end(err error) {
+	s.once.Do(func() { close(s.done) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.close(err)
+}
+
+// close records err and closes the channel. It must be called with mu held.
+func (s *Subscription[T]) close(err error) {
+	if s.err != nil {
+		return
+	}
+	s.errMu.Lock()
+	s.err = err
+	s.errMu.Unlock()
+	s.once.Do(func() { close(s.done) })
+	close(s.ch)
+	s.broker.mu.Lock()
+	delete(s.broker.subs, s)
+	s.broker.mu.Unlock()
+}
+
+// Unsubscribe ends the subscription with context.Canceled.
+func (s *Subscription[T]) Unsubscribe() {
+	s.end(context.Canceled)
+}
+
+// C returns the channel of received messages. It is closed when the subscription ends.
+func (s *Subscription[T]) C() <-chan Message[T] {
+	return s.ch
+}
+
+// Err returns why the subscription ended, or nil if it is active:
+// ErrBrokerClosed or ErrSubscriberEvicted (both wrapping ErrChClosed) if the broker ended it,
+// or the subscription context's error if the subscriber canceled it.
+func (s *Subscription[T]) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// Recv receives the next message.
+// Once the subscription has ended and its buffer is drained, it returns Err.
+// If ctx is done first, it returns the context's error.
+func (s *Subscription[T]) Recv(ctx context.Context) (Message[T], error) {
+	msg, err := RecvContext(ctx, s.ch)
+	if err == ErrChClosed {
+		return msg, s.Err()
+	}
+	return msg, err
+}
+
+// All returns a sequence of the received messages that ends when the subscription ends.
+func (s *Subscription[T]) All() iter.Seq[Message[T]] {
+	return func(yield func(Message[T]) bool) {
+		for msg := range s.ch {
+			if !yield(msg) {
+				return
+			}
+		}
+	}
+}
+
+// MatchTopic reports whether topic matches pattern.
+// Segments are separated by dots; "*" matches exactly one segment and "#" matches zero or more segments.
+//
+// For example:
+//
+//	MatchTopic("orders.*", "orders.created") = true
+//	MatchTopic("orders.#", "orders") = true
+//	MatchTopic("orders.*", "orders.eu.created") = false
+func MatchTopic(pattern, topic string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+func matchSegments(pattern, topic []string) bool {
+	if len(pattern) == 0 {
+		return len(topic) == 0
+	}
+	if pattern[0] == "#" {
+		for i := 0; i <= len(topic); i++ {
+			if matchSegments(pattern[1:], topic[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(topic) == 0 || (pattern[0] != "*" && pattern[0] != topic[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], topic[1:])
+}
@@ -0,0 +1,103 @@
+package util_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestMatchTopic(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(util.MatchTopic("orders.created", "orders.created"))
+	a.False(util.MatchTopic("orders.created", "orders.deleted"))
+	a.True(util.MatchTopic("orders.*", "orders.created"))
+	a.False(util.MatchTopic("orders.*", "orders"))
+	a.False(util.MatchTopic("orders.*", "orders.eu.created"))
+	a.True(util.MatchTopic("orders.#", "orders"))
+	a.True(util.MatchTopic("orders.#", "orders.eu.created"))
+	a.True(util.MatchTopic("#.created", "orders.eu.created"))
+	a.True(util.MatchTopic("*.eu.*", "orders.eu.created"))
+	a.False(util.MatchTopic("*.us.*", "orders.eu.created"))
+}
+
+func TestBroker(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	b := util.NewBroker[int]()
+	all := b.Subscribe(ctx, "#", util.Subscriber{Buffer: 10})
+	orders := b.Subscribe(ctx, "orders.*", util.Subscriber{Buffer: 10})
+
+	n, err := b.Publish(ctx, "orders.created", 1)
+	a.NoError(err)
+	a.Equal(2, n)
+	n, err = b.Publish(ctx, "users.created", 2)
+	a.NoError(err)
+	a.Equal(1, n)
+
+	msg, err := orders.Recv(ctx)
+	a.NoError(err)
+	a.Equal(util.Message[int]{Topic: "orders.created", Value: 1}, msg)
+
+	b.Close()
+	a.ErrorIs(all.Err(), util.ErrBrokerClosed)
+	a.Equal([]int{1, 2}, slices.Collect(util.Map(all.All(), func(m util.Message[int]) int { return m.Value })))
+	_, err = orders.Recv(ctx)
+	a.ErrorIs(err, util.ErrChClosed)
+
+	_, err = b.Publish(ctx, "orders.created", 3)
+	a.ErrorIs(err, util.ErrBrokerClosed)
+	late := b.Subscribe(ctx, "#", util.Subscriber{})
+	a.ErrorIs(late.Err(), util.ErrBrokerClosed)
+}
+
+func TestBrokerSubscriberCancel(t *testing.T) {
+	a := assert.New(t)
+
+	b := util.NewBroker[string]()
+	defer b.Close()
+	subCtx, cancel := context.WithCancel(context.Background())
+	sub := b.Subscribe(subCtx, "news", util.Subscriber{})
+	cancel()
+	_, err := sub.Recv(context.Background())
+	a.ErrorIs(err, context.Canceled)
+	a.NotErrorIs(err, util.ErrChClosed)
+
+	n, err := b.Publish(context.Background(), "news", "hello")
+	a.NoError(err)
+	a.Equal(0, n)
+}
+
+func TestBrokerOverflow(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+
+	b := util.NewBroker[int]()
+	defer b.Close()
+	drop := b.Subscribe(ctx, "t", util.Subscriber{Buffer: 1, Policy: util.OverflowDrop})
+	evict := b.Subscribe(ctx, "t", util.Subscriber{Buffer: 1, Policy: util.OverflowEvict})
+	block := b.Subscribe(ctx, "t", util.Subscriber{Buffer: 1, Policy: util.OverflowBlock})
+
+	n, err := b.Publish(ctx, "t", 1)
+	a.NoError(err)
+	a.Equal(3, n)
+
+	timeout, cancel := context.WithCancel(ctx)
+	cancel()
+	n, err = b.Publish(timeout, "t", 2)
+	a.ErrorIs(err, context.Canceled)
+	a.Equal(0, n)
+
+	a.NoError(drop.Err())
+	a.ErrorIs(evict.Err(), util.ErrSubscriberEvicted)
+	a.NoError(block.Err())
+
+	msg, err := drop.Recv(ctx)
+	a.NoError(err)
+	a.Equal(1, msg.Value)
+}
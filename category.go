@@ -0,0 +1,143 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+	"sync"
+)
+
+// ErrorCategory is a semantic class of errors, independent of their concrete type.
+type ErrorCategory string
+
+const (
+	CategoryNotFound     ErrorCategory = "not_found"
+	CategoryConflict     ErrorCategory = "conflict"
+	CategoryRetryable    ErrorCategory = "retryable"
+	CategoryTimeout      ErrorCategory = "timeout"
+	CategoryInvalidInput ErrorCategory = "invalid_input"
+	CategoryUnauthorized ErrorCategory = "unauthorized"
+)
+
+// categoryError tags an error with categories.
+type categoryError struct {
+	err        error
+	categories []ErrorCategory
+}
+
+func (e *categoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categoryError) Unwrap() error {
+	return e.err
+}
+
+func (e *categoryError) ErrorCategories() []ErrorCategory {
+	return e.categories
+}
+
+// WithCategory returns err tagged with categories. The message and chain of err are unchanged.
+// It returns nil if err is nil.
+func WithCategory(err error, categories ...ErrorCategory) error {
+	if err == nil {
+		return nil
+	}
+	return &categoryError{err: err, categories: categories}
+}
+
+// CategoryRegistry maps error types and sentinel errors that cannot be tagged with WithCategory,
+// such as those of third-party packages, to categories.
+// It is safe for concurrent use.
+type CategoryRegistry struct {
+	mu      sync.RWMutex
+	matches []categoryMatch
+}
+
+type categoryMatch struct {
+	match      func(err error) bool
+	categories []ErrorCategory
+}
+
+// DefaultCategories is the registry used by IsCategory and ErrorCategories.
+// It maps context.DeadlineExceeded to CategoryTimeout, fs.ErrNotExist to CategoryNotFound
+// and fs.ErrExist to CategoryConflict.
+var DefaultCategories = NewCategoryRegistry()
+
+func init() {
+	DefaultCategories.RegisterSentinel(context.DeadlineExceeded, CategoryTimeout)
+	DefaultCategories.RegisterSentinel(fs.ErrNotExist, CategoryNotFound)
+	DefaultCategories.RegisterSentinel(fs.ErrExist, CategoryConflict)
+}
+
+// NewCategoryRegistry returns an empty CategoryRegistry.
+func NewCategoryRegistry() *CategoryRegistry {
+	return &CategoryRegistry{}
+}
+
+// RegisterFunc assigns categories to the errors for which match returns true.
+func (r *CategoryRegistry) RegisterFunc(match func(err error) bool, categories ...ErrorCategory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.matches = append(r.matches, categoryMatch{match: match, categories: categories})
+}
+
+// RegisterSentinel assigns categories to the errors that match target with errors.Is.
+func (r *CategoryRegistry) RegisterSentinel(target error, categories ...ErrorCategory) {
+	r.RegisterFunc(func(err error) bool { return errors.Is(err, target) }, categories...)
+}
+
+// RegisterCategoryType assigns categories to the errors whose chain contains a T, as found by ErrorAs.
+func RegisterCategoryType[T error](r *CategoryRegistry, categories ...ErrorCategory) {
+	r.RegisterFunc(func(err error) bool {
+		_, ok := ErrorAs[T](err)
+		return ok
+	}, categories...)
+}
+
+// Categories returns the categories of err: those tagged with WithCategory or reported by an
+// ErrorCategories() []ErrorCategory method anywhere in err's tree, followed by those registered in r.
+// Each category appears once.
+func (r *CategoryRegistry) Categories(err error) []ErrorCategory {
+	if err == nil {
+		return nil
+	}
+	var categories []ErrorCategory
+	for e := range Walk(err) {
+		if c, ok := e.(interface{ ErrorCategories() []ErrorCategory }); ok {
+			categories = append(categories, c.ErrorCategories()...)
+		}
+	}
+	r.mu.RLock()
+	for _, m := range r.matches {
+		if m.match(err) {
+			categories = append(categories, m.categories...)
+		}
+	}
+	r.mu.RUnlock()
+
+	var unique []ErrorCategory
+	for _, c := range categories {
+		if !slices.Contains(unique, c) {
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
+
+// IsCategory reports whether err has the category according to r. See Categories.
+func (r *CategoryRegistry) IsCategory(err error, category ErrorCategory) bool {
+	return slices.Contains(r.Categories(err), category)
+}
+
+// ErrorCategories returns the categories of err according to DefaultCategories.
+func ErrorCategories(err error) []ErrorCategory {
+	return DefaultCategories.Categories(err)
+}
+
+// IsCategory reports whether err has the category according to DefaultCategories.
+// Like ErrorAs, it looks through the whole chain of err.
+func IsCategory(err error, category ErrorCategory) bool {
+	return DefaultCategories.IsCategory(err, category)
+}
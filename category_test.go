@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestWithCategory(t *testing.T) {
+	a := assert.New(t)
+
+	base := errors.New("user 42 not found")
+	err := util.WithCategory(base, util.CategoryNotFound)
+	a.EqualError(err, "user 42 not found")
+	a.ErrorIs(err, base)
+
+	wrapped := fmt.Errorf("get profile: %w", err)
+	a.True(util.IsCategory(wrapped, util.CategoryNotFound))
+	a.False(util.IsCategory(wrapped, util.CategoryConflict))
+
+	joined := errors.Join(wrapped, util.WithCategory(errors.New("busy"), util.CategoryRetryable, util.CategoryTimeout))
+	a.Equal([]util.ErrorCategory{util.CategoryNotFound, util.CategoryRetryable, util.CategoryTimeout}, util.ErrorCategories(joined))
+
+	a.Nil(util.WithCategory(nil, util.CategoryNotFound))
+	a.Empty(util.ErrorCategories(nil))
+}
+
+func TestDefaultCategories(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := os.Open("/nonexistent/file")
+	a.True(util.IsCategory(err, util.CategoryNotFound))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+	a.True(util.IsCategory(fmt.Errorf("call: %w", ctx.Err()), util.CategoryTimeout))
+}
+
+func TestCategoryRegistry(t *testing.T) {
+	a := assert.New(t)
+
+	errConflict := errors.New("version mismatch")
+	r := util.NewCategoryRegistry()
+	r.RegisterSentinel(errConflict, util.CategoryConflict)
+	util.RegisterCategoryType[*MyError](r, util.CategoryInvalidInput)
+	r.RegisterFunc(func(err error) bool {
+		e, ok := util.ErrorAs[*AnotherError](err)
+		return ok && e.Reason == "token expired"
+	}, util.CategoryUnauthorized)
+
+	a.True(r.IsCategory(fmt.Errorf("save: %w", errConflict), util.CategoryConflict))
+	a.True(r.IsCategory(fmt.Errorf("parse: %w", &MyError{Code: 400}), util.CategoryInvalidInput))
+	a.True(r.IsCategory(&AnotherError{Reason: "token expired"}, util.CategoryUnauthorized))
+	a.False(r.IsCategory(&AnotherError{Reason: "other"}, util.CategoryUnauthorized))
+
+	// Tags and registrations combine without duplicates.
+	err := util.WithCategory(&MyError{}, util.CategoryInvalidInput, util.CategoryRetryable)
+	a.Equal([]util.ErrorCategory{util.CategoryInvalidInput, util.CategoryRetryable}, r.Categories(err))
+	// A custom registry does not include the defaults.
+	a.False(r.IsCategory(os.ErrNotExist, util.CategoryNotFound))
+}
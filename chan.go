@@ -0,0 +1,220 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// SeqFromChan returns a sequence of the values received from the channel ch.
+// The sequence ends when ch is closed or ctx is done.
+// After the sequence ends, err reports why: nil if ch was closed,
+// or the context's error if the context was done.
+func SeqFromChan[T any](ctx context.Context, ch <-chan T) (seq iter.Seq[T], err func() error) {
+	var seqErr error
+	seq = func(yield func(T) bool) {
+		seqErr = nil
+		for {
+			v, err := RecvContext(ctx, ch)
+			if errors.Is(err, ErrChClosed) {
+				return
+			}
+			if err != nil {
+				seqErr = err
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+	return seq, func() error { return seqErr }
+}
+
+// ChanFromSeq returns a channel with the given buffer size that receives the values of seq.
+// A producer goroutine iterates seq and closes the channel when seq ends or ctx is done.
+// Once ctx is done, the producer stops iterating seq without waiting for a receiver.
+func ChanFromSeq[T any](ctx context.Context, seq iter.Seq[T], buffer int) <-chan T {
+	ch := make(chan T, buffer)
+	go func() {
+		defer close(ch)
+		for v := range seq {
+			if SendContext(ctx, ch, v) != nil {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// MergeChans forwards the values received from all chans to a single channel.
+// The returned channel is closed when all chans are closed or ctx is done.
+func MergeChans[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, err := RecvContext(ctx, ch)
+				if err != nil {
+					return
+				}
+				if SendContext(ctx, out, v) != nil {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// OverflowPolicy decides what happens when a subscriber's buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the subscriber has room, holding back every other subscriber.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop discards the value for that subscriber only.
+	OverflowDrop
+	// OverflowEvict closes the subscriber's channel and stops sending to it.
+	OverflowEvict
+)
+
+// Subscriber describes one output of Broadcast.
+type Subscriber struct {
+	// Buffer is the capacity of the subscriber's channel.
+	Buffer int
+	// Policy is applied when the subscriber's channel is full.
+	Policy OverflowPolicy
+}
+
+// Broadcast copies each value received from in to every subscriber.
+// It returns one channel per subscriber, in the same order.
+// All channels are closed when in is closed or ctx is done.
+func Broadcast[T any](ctx context.Context, in <-chan T, subscribers ...Subscriber) []<-chan T {
+	outs := make([]chan T, len(subscribers))
+	result := make([]<-chan T, len(subscribers))
+	for i, sub := range subscribers {
+		outs[i] = make(chan T, sub.Buffer)
+		result[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				if out != nil {
+					close(out)
+				}
+			}
+		}()
+		for {
+			v, err := RecvContext(ctx, in)
+			if err != nil {
+				return
+			}
+			for i, out := range outs {
+				if out == nil {
+					continue
+				}
+				switch subscribers[i].Policy {
+				case OverflowDrop:
+					_ = TrySend(out, v)
+				case OverflowEvict:
+					if TrySend(out, v) != nil {
+						close(out)
+						outs[i] = nil
+					}
+				default:
+					if SendContext(ctx, out, v) != nil {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return result
+}
+
+// Tee copies each value received from in to n unbuffered channels.
+// Every value is delivered to all channels, so the slowest receiver sets the pace.
+// All channels are closed when in is closed or ctx is done.
+func Tee[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	return Broadcast(ctx, in, make([]Subscriber, n)...)
+}
+
+// Partition routes each value received from in to the channel of the key returned by keyOf.
+// Values whose key is not one of keys are sent to rest.
+// All channels are unbuffered and must be received from; they are closed when in is closed or ctx is done.
+func Partition[T any, K comparable](ctx context.Context, in <-chan T, keyOf func(v T) K, keys ...K) (parts map[K]<-chan T, rest <-chan T) {
+	outs := make(map[K]chan T, len(keys))
+	parts = make(map[K]<-chan T, len(keys))
+	for _, key := range keys {
+		if _, ok := outs[key]; !ok {
+			outs[key] = make(chan T)
+			parts[key] = outs[key]
+		}
+	}
+	restCh := make(chan T)
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+			close(restCh)
+		}()
+		for {
+			v, err := RecvContext(ctx, in)
+			if err != nil {
+				return
+			}
+			out, ok := outs[keyOf(v)]
+			if !ok {
+				out = restCh
+			}
+			if SendContext(ctx, out, v) != nil {
+				return
+			}
+		}
+	}()
+	return parts, restCh
+}
+
+// RecvAny receives a value from whichever of chans is ready first.
+// It returns the index of that channel in chans together with the value.
+// Closed channels are removed from the set while waiting, and nil channels are ignored;
+// ErrChClosed is returned only once every channel is closed.
+// If the context is done, it returns the context's error.
+func RecvAny[T any](ctx context.Context, chans ...<-chan T) (index int, v T, err error) {
+	cases := make([]reflect.SelectCase, 0, len(chans)+1)
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	indexes := make([]int, 0, len(chans))
+	for i, ch := range chans {
+		if ch == nil {
+			continue
+		}
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+		indexes = append(indexes, i)
+	}
+	for len(indexes) > 0 {
+		chosen, recv, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return -1, v, ctx.Err()
+		}
+		if !ok {
+			cases = slices.Delete(cases, chosen, chosen+1)
+			indexes = slices.Delete(indexes, chosen-1, chosen)
+			continue
+		}
+		reflect.ValueOf(&v).Elem().Set(recv)
+		return indexes[chosen-1], v, nil
+	}
+	return -1, v, ErrChClosed
+}
@@ -0,0 +1,182 @@
+package util_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestSeqFromChan(t *testing.T) {
+	a := assert.New(t)
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	seq, errFn := util.SeqFromChan(context.Background(), ch)
+	a.Equal([]int{2, 4, 6}, slices.Collect(util.Map(seq, func(v int) int { return v * 2 })))
+	a.NoError(errFn())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch = make(chan int, 1)
+	ch <- 1
+	seq, errFn = util.SeqFromChan(ctx, ch)
+	var got []int
+	for v := range seq {
+		got = append(got, v)
+		cancel()
+	}
+	a.Equal([]int{1}, got)
+	a.ErrorIs(errFn(), context.Canceled)
+}
+
+func TestChanFromSeq(t *testing.T) {
+	a := assert.New(t)
+
+	ch := util.ChanFromSeq(context.Background(), util.Range[int](5), 0)
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+	a.Equal([]int{0, 1, 2, 3, 4}, got)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	infinite := func(yield func(int) bool) {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	ch = util.ChanFromSeq(ctx, infinite, 0)
+	a.Equal(0, <-ch)
+	cancel()
+	<-stopped
+	_, err := util.DrainContext(context.Background(), ch)
+	a.NoError(err)
+}
+
+func sendAll[T any](values ...T) <-chan T {
+	ch := make(chan T, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	return ch
+}
+
+func TestMergeChans(t *testing.T) {
+	a := assert.New(t)
+
+	merged := util.MergeChans(context.Background(), sendAll(1, 2), sendAll(3), sendAll[int]())
+	var got []int
+	for v := range merged {
+		got = append(got, v)
+	}
+	a.ElementsMatch([]int{1, 2, 3}, got)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	merged = util.MergeChans(ctx, make(chan int))
+	cancel()
+	_, err := util.DrainContext(context.Background(), merged)
+	a.NoError(err)
+}
+
+func TestBroadcast(t *testing.T) {
+	a := assert.New(t)
+
+	in := make(chan int)
+	outs := util.Broadcast(context.Background(), in,
+		util.Subscriber{Buffer: 3, Policy: util.OverflowBlock},
+		util.Subscriber{Buffer: 1, Policy: util.OverflowDrop},
+		util.Subscriber{Buffer: 1, Policy: util.OverflowEvict},
+	)
+	for i := range 3 {
+		in <- i
+	}
+	close(in)
+
+	values, err := util.RecvN(context.Background(), outs[0], 4)
+	a.ErrorIs(err, util.ErrChClosed)
+	a.Equal([]int{0, 1, 2}, values)
+
+	values, _ = util.RecvN(context.Background(), outs[1], 4)
+	a.Equal([]int{0}, values)
+
+	values, _ = util.RecvN(context.Background(), outs[2], 4)
+	a.Equal([]int{0}, values)
+}
+
+func TestTee(t *testing.T) {
+	a := assert.New(t)
+
+	outs := util.Tee(context.Background(), sendAll(1, 2, 3), 2)
+	a.Len(outs, 2)
+	got := make([][]int, 2)
+	for range 3 {
+		for i, out := range outs {
+			got[i] = append(got[i], <-out)
+		}
+	}
+	a.Equal([][]int{{1, 2, 3}, {1, 2, 3}}, got)
+}
+
+func TestPartition(t *testing.T) {
+	a := assert.New(t)
+
+	parts, rest := util.Partition(context.Background(), sendAll(1, 2, 3, 4, 5, 6), func(v int) int { return v % 3 }, 0, 1)
+	collect := func(ch <-chan int) <-chan []int {
+		done := make(chan []int, 1)
+		go func() {
+			var values []int
+			for v := range ch {
+				values = append(values, v)
+			}
+			done <- values
+		}()
+		return done
+	}
+	zero, one, other := collect(parts[0]), collect(parts[1]), collect(rest)
+	a.Equal([]int{3, 6}, <-zero)
+	a.Equal([]int{1, 4}, <-one)
+	a.Equal([]int{2, 5}, <-other)
+}
+
+func TestRecvAny(t *testing.T) {
+	a := assert.New(t)
+
+	closed := make(chan int)
+	close(closed)
+	ready := make(chan int, 1)
+	ready <- 7
+	index, v, err := util.RecvAny(context.Background(), closed, nil, ready)
+	a.NoError(err)
+	a.Equal(2, index)
+	a.Equal(7, v)
+
+	_, _, err = util.RecvAny(context.Background(), closed, sendAll[int]())
+	a.ErrorIs(err, util.ErrChClosed)
+
+	_, _, err = util.RecvAny[int](context.Background())
+	a.ErrorIs(err, util.ErrChClosed)
+
+	errs := make(chan error, 1)
+	errs <- nil
+	index, recvErr, err := util.RecvAny(context.Background(), errs)
+	a.NoError(err)
+	a.Equal(0, index)
+	a.Nil(recvErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = util.RecvAny(ctx, closed, make(chan int))
+	a.ErrorIs(err, context.Canceled)
+}
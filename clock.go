@@ -0,0 +1,228 @@
+package util
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clocker is a source of time that can be replaced in tests, such as by a FakeClock.
+// The name avoids the existing Clock function.
+type Clocker interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTimer creates a Timer that sends the current time on its channel after d.
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a Ticker that sends the current time on its channel every d.
+	NewTicker(d time.Duration) Ticker
+	// Sleep pauses the current goroutine for d.
+	Sleep(d time.Duration)
+}
+
+// Timer is the Clocker counterpart of *time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the Clocker counterpart of *time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// SystemClock is the Clocker backed by the time package.
+var SystemClock Clocker = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTimer(d time.Duration) Timer         { return systemTimer{time.NewTimer(d)} }
+func (systemClock) NewTicker(d time.Duration) Ticker       { return systemTicker{time.NewTicker(d)} }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+type systemTimer struct{ *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time { return t.Timer.C }
+
+type systemTicker struct{ *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// clockOrSystem returns c, or SystemClock if c is nil.
+func clockOrSystem(c Clocker) Clocker {
+	if c == nil {
+		return SystemClock
+	}
+	return c
+}
+
+// FakeClock is a Clocker whose time only moves when Advance or Set is called.
+// Timers and tickers fire in order of their due time as the clock passes it.
+// It is safe for concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	seq    uint64
+	timers []*fakeTimer
+}
+
+var _ Clocker = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1), period: d}
+	t.Reset(d)
+	return fakeTicker{t}
+}
+
+// Sleep blocks until the clock has been advanced by d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d, firing the timers that become due in order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	target := c.now.Add(d)
+	for len(c.timers) > 0 && !c.timers[0].when.After(target) {
+		t := c.timers[0]
+		c.now = MaxTime(c.now, t.when)
+		_ = TrySend(t.ch, c.now)
+		c.removeLocked(t)
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+			c.addLocked(t)
+		}
+	}
+	c.now = MaxTime(c.now, target)
+}
+
+// Set moves the clock to t, firing the timers that become due in order.
+// Setting the clock backwards changes the time without firing timers.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	d := t.Sub(c.now)
+	if d < 0 {
+		c.now = t
+	}
+	c.mu.Unlock()
+	if d > 0 {
+		c.Advance(d)
+	}
+}
+
+// BlockUntil blocks until at least n timers, tickers or sleepers are waiting on the clock.
+// It lets a test advance the clock only after the code under test has started waiting.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// addLocked inserts t keeping c.timers sorted by due time, then creation order. It must be called with mu held.
+func (c *FakeClock) addLocked(t *fakeTimer) {
+	i, _ := slices.BinarySearchFunc(c.timers, t, func(x, y *fakeTimer) int {
+		if n := x.when.Compare(y.when); n != 0 {
+			return n
+		}
+		return cmp.Compare(x.seq, y.seq)
+	})
+	c.timers = slices.Insert(c.timers, i, t)
+	c.cond.Broadcast()
+}
+
+// removeLocked removes t and reports whether it was waiting. It must be called with mu held.
+func (c *FakeClock) removeLocked(t *fakeTimer) bool {
+	i := slices.Index(c.timers, t)
+	if i < 0 {
+		return false
+	}
+	c.timers = slices.Delete(c.timers, i, i+1)
+	return true
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	ch     chan time.Time
+	when   time.Time
+	period time.Duration
+	seq    uint64
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.removeLocked(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.removeLocked(t)
+	if d <= 0 && t.period == 0 {
+		_ = TrySend(t.ch, c.now)
+		return active
+	}
+	c.seq++
+	t.seq = c.seq
+	t.when = c.now.Add(d)
+	c.addLocked(t)
+	return active
+}
+
+type fakeTicker struct{ *fakeTimer }
+
+func (t fakeTicker) Stop() { t.fakeTimer.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	t.clock.mu.Lock()
+	t.period = d
+	t.clock.mu.Unlock()
+	t.fakeTimer.Reset(d)
+}
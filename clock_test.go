@@ -0,0 +1,88 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestFakeClock(t *testing.T) {
+	a := assert.New(t)
+
+	start := date(2025, 8, 8)
+	clock := util.NewFakeClock(start)
+	a.Equal(start, clock.Now())
+
+	late := clock.NewTimer(3 * time.Second)
+	early := clock.After(time.Second)
+	stopped := clock.NewTimer(2 * time.Second)
+	a.True(stopped.Stop())
+	a.False(stopped.Stop())
+
+	clock.Advance(time.Second)
+	a.Equal(start.Add(time.Second), <-early)
+	_, err := util.TryRecv(late.C())
+	a.ErrorIs(err, util.ErrChEmpty)
+
+	clock.Advance(5 * time.Second)
+	a.Equal(start.Add(3*time.Second), <-late.C(), "a timer receives its due time")
+	a.Equal(6*time.Second, clock.Since(start))
+	_, err = util.TryRecv(stopped.C())
+	a.ErrorIs(err, util.ErrChEmpty)
+
+	a.False(late.Reset(time.Second))
+	clock.Set(start.Add(7 * time.Second))
+	a.Equal(start.Add(7*time.Second), <-late.C())
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	a := assert.New(t)
+
+	start := date(2025, 8, 8)
+	clock := util.NewFakeClock(start)
+	ticker := clock.NewTicker(time.Minute)
+	var got []time.Time
+	for range 3 {
+		clock.Advance(time.Minute)
+		got = append(got, <-ticker.C())
+	}
+	a.Equal([]time.Time{start.Add(time.Minute), start.Add(2 * time.Minute), start.Add(3 * time.Minute)}, got)
+
+	ticker.Reset(time.Hour)
+	clock.Advance(time.Minute)
+	_, err := util.TryRecv(ticker.C())
+	a.ErrorIs(err, util.ErrChEmpty)
+	ticker.Stop()
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	a := assert.New(t)
+
+	clock := util.NewFakeClock(date(2025, 8, 8))
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(time.Hour)
+		close(done)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		a.Fail("Sleep did not return")
+	}
+}
+
+func TestSystemClock(t *testing.T) {
+	a := assert.New(t)
+
+	timer := util.SystemClock.NewTimer(time.Millisecond)
+	<-timer.C()
+	ticker := util.SystemClock.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+	a.Greater(util.SystemClock.Since(time.Now().Add(-time.Second)), time.Duration(0))
+}
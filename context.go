@@ -5,14 +5,61 @@ import (
 	"errors"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 )
 
 // WithNotifyContext wraps signal.NotifyContext. It returns a copy of the parent
-// context that is canceled when the process receives a SIGTERM, os.Interrupt, or
-// os.Kill signal.
+// context that is canceled when the process receives one of ShutdownSignals.
 func WithNotifyContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
-	return signal.NotifyContext(parent, syscall.SIGTERM, os.Interrupt, os.Kill)
+	return signal.NotifyContext(parent, ShutdownSignals...)
+}
+
+// WithNotifyCauseContext is like WithNotifyContext, but listens for the given signals
+// and records the received signal as the context's cause.
+// If no signals are given, ShutdownSignals is used.
+// Use context.Cause and ErrorAs[*SignalError] to find out which signal canceled the context.
+func WithNotifyCauseContext(parent context.Context, signals ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	if len(signals) == 0 {
+		signals = ShutdownSignals
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	go func() {
+		select {
+		case sig := <-ch:
+			cancel(&SignalError{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			cancel(nil)
+		})
+	}
+	return ctx, stop
+}
+
+// SignalError is the cause of a context canceled by WithNotifyCauseContext.
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "received signal: " + e.Signal.String()
+}
+
+// ExitCode returns the conventional shell exit code for the signal, 128 plus the signal number
+// (e.g. 130 for SIGINT, 143 for SIGTERM). It returns 1 if the signal has no number.
+func (e *SignalError) ExitCode() int {
+	if sig, ok := e.Signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 1
 }
 
 var ErrChClosed = errors.New("ch closed")
@@ -33,3 +80,94 @@ func RecvContext[T any](ctx context.Context, ch <-chan T) (T, error) {
 		return v, nil
 	}
 }
+
+var (
+	ErrChEmpty = errors.New("ch empty")
+	ErrChFull  = errors.New("ch full")
+)
+
+// SendContext sends v to the channel ch.
+// It returns when the value is sent or the context is done.
+// If the context is done, it returns the context's error.
+//
+// Like a plain send, it panics if ch is closed.
+func SendContext[T any](ctx context.Context, ch chan<- T, v T) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case ch <- v:
+		return nil
+	}
+}
+
+// TryRecv receives a value from the channel ch without blocking.
+// If no value is ready, it returns ErrChEmpty.
+// If the channel is closed, it returns ErrChClosed.
+func TryRecv[T any](ch <-chan T) (T, error) {
+	var zero T
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			return zero, ErrChClosed
+		}
+		return v, nil
+	default:
+		return zero, ErrChEmpty
+	}
+}
+
+// TrySend sends v to the channel ch without blocking.
+// If the channel is not ready to receive, it returns ErrChFull.
+//
+// Like a plain send, it panics if ch is closed.
+func TrySend[T any](ch chan<- T, v T) error {
+	select {
+	case ch <- v:
+		return nil
+	default:
+		return ErrChFull
+	}
+}
+
+// RecvTimeout receives a value from the channel ch, waiting at most timeout.
+// If the timeout elapses, it returns context.DeadlineExceeded.
+// Otherwise it behaves like RecvContext.
+func RecvTimeout[T any](ctx context.Context, ch <-chan T, timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return RecvContext(ctx, ch)
+}
+
+// RecvN receives up to n values from the channel ch.
+// It returns the values received so far together with the error that stopped it:
+// the context's error if the context is done, or ErrChClosed if the channel is closed
+// before n values arrive. If n values are received, the error is nil.
+func RecvN[T any](ctx context.Context, ch <-chan T, n int) ([]T, error) {
+	// n may be huge when collecting until the context ends, so only a small buffer is preallocated.
+	values := make([]T, 0, min(max(n, 0), 64))
+	for len(values) < n {
+		v, err := RecvContext(ctx, ch)
+		if err != nil {
+			return values, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// DrainContext receives and discards values from the channel ch until it is closed.
+// It returns the number of discarded values.
+// If the context is done first, it returns the context's error.
+// A closed channel is the normal end of draining, so ErrChClosed is never returned.
+func DrainContext[T any](ctx context.Context, ch <-chan T) (n int, err error) {
+	for {
+		_, err = RecvContext(ctx, ch)
+		if errors.Is(err, ErrChClosed) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
@@ -0,0 +1,123 @@
+package util_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestRecvContext(t *testing.T) {
+	a := assert.New(t)
+
+	ch := make(chan int, 1)
+	ch <- 1
+	v, err := util.RecvContext(context.Background(), ch)
+	a.NoError(err)
+	a.Equal(1, v)
+
+	close(ch)
+	_, err = util.RecvContext(context.Background(), ch)
+	a.ErrorIs(err, util.ErrChClosed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = util.RecvContext(ctx, make(chan int))
+	a.ErrorIs(err, context.Canceled)
+}
+
+func TestSendContext(t *testing.T) {
+	a := assert.New(t)
+
+	ch := make(chan int, 1)
+	a.NoError(util.SendContext(context.Background(), ch, 1))
+	a.Equal(1, <-ch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a.ErrorIs(util.SendContext(ctx, make(chan int), 1), context.Canceled)
+}
+
+func TestTryRecvSend(t *testing.T) {
+	a := assert.New(t)
+
+	ch := make(chan int, 1)
+	_, err := util.TryRecv(ch)
+	a.ErrorIs(err, util.ErrChEmpty)
+
+	a.NoError(util.TrySend(ch, 1))
+	a.ErrorIs(util.TrySend(ch, 2), util.ErrChFull)
+
+	v, err := util.TryRecv(ch)
+	a.NoError(err)
+	a.Equal(1, v)
+
+	close(ch)
+	_, err = util.TryRecv(ch)
+	a.ErrorIs(err, util.ErrChClosed)
+}
+
+func TestRecvTimeout(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := util.RecvTimeout(context.Background(), make(chan int), time.Millisecond)
+	a.ErrorIs(err, context.DeadlineExceeded)
+
+	ch := make(chan int, 1)
+	ch <- 1
+	v, err := util.RecvTimeout(context.Background(), ch, time.Second)
+	a.NoError(err)
+	a.Equal(1, v)
+}
+
+func TestRecvN(t *testing.T) {
+	a := assert.New(t)
+
+	ch := make(chan int, 5)
+	for i := range 5 {
+		ch <- i
+	}
+	values, err := util.RecvN(context.Background(), ch, 3)
+	a.NoError(err)
+	a.Equal([]int{0, 1, 2}, values)
+
+	close(ch)
+	values, err = util.RecvN(context.Background(), ch, 3)
+	a.ErrorIs(err, util.ErrChClosed)
+	a.Equal([]int{3, 4}, values)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	values, err = util.RecvN(ctx, make(chan int), 3)
+	a.ErrorIs(err, context.Canceled)
+	a.Empty(values)
+
+	ch = make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	values, err = util.RecvN(context.Background(), ch, math.MaxInt)
+	a.ErrorIs(err, util.ErrChClosed)
+	a.Equal([]int{1, 2}, values)
+}
+
+func TestDrainContext(t *testing.T) {
+	a := assert.New(t)
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	n, err := util.DrainContext(context.Background(), ch)
+	a.NoError(err)
+	a.Equal(2, n)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = util.DrainContext(ctx, make(chan int))
+	a.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package util_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestWithNotifyCauseContext(t *testing.T) {
+	a := assert.New(t)
+
+	ctx, stop := util.WithNotifyCauseContext(context.Background(), syscall.SIGUSR1)
+	defer stop()
+
+	p, err := os.FindProcess(os.Getpid())
+	a.NoError(err)
+	a.NoError(p.Signal(syscall.SIGUSR1))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		a.FailNow("context was not canceled")
+	}
+	sigErr, ok := util.ErrorAs[*util.SignalError](context.Cause(ctx))
+	a.True(ok)
+	a.Equal(syscall.SIGUSR1, sigErr.Signal)
+	a.Equal(128+int(syscall.SIGUSR1), sigErr.ExitCode())
+
+	ctx, stop = util.WithNotifyCauseContext(context.Background())
+	stop()
+	a.ErrorIs(context.Cause(ctx), context.Canceled)
+	a.Equal(130, (&util.SignalError{Signal: os.Interrupt}).ExitCode())
+	a.Equal(143, (&util.SignalError{Signal: syscall.SIGTERM}).ExitCode())
+}
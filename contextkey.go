@@ -0,0 +1,84 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ContextKey is a typed key for context values.
+// Each key created by NewContextKey is distinct, even if two keys share a name.
+type ContextKey[T any] struct {
+	name string
+}
+
+type registeredContextKey interface {
+	String() string
+	lookup(ctx context.Context) (any, bool)
+}
+
+var contextKeys struct {
+	sync.Mutex
+	keys []registeredContextKey
+}
+
+// NewContextKey returns a new key for values of type T and registers it for DumpContext.
+// The name is only used for display and may be empty.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	k := &ContextKey[T]{name: name}
+	contextKeys.Lock()
+	defer contextKeys.Unlock()
+	contextKeys.keys = append(contextKeys.keys, k)
+	return k
+}
+
+// String returns the key's name and value type, e.g. "requestID(string)",
+// or only the value type if the key has no name.
+func (k *ContextKey[T]) String() string {
+	if k.name == "" {
+		return GetTypeName[T]()
+	}
+	return k.name + "(" + GetTypeName[T]() + ")"
+}
+
+// With returns a copy of ctx carrying v under k.
+func (k *ContextKey[T]) With(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Get returns the value stored under k and whether it was present.
+func (k *ContextKey[T]) Get(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// MustGet returns the value stored under k.
+// It panics with a message naming the key if the value is missing.
+func (k *ContextKey[T]) MustGet(ctx context.Context) T {
+	v, ok := k.Get(ctx)
+	if !ok {
+		panic(fmt.Sprintf("context key %s is not set", k))
+	}
+	return v
+}
+
+func (k *ContextKey[T]) lookup(ctx context.Context) (any, bool) {
+	return k.Get(ctx)
+}
+
+// DumpContext returns a readable listing of the values that ctx carries for keys created by NewContextKey,
+// one "key = value" per line in key creation order.
+func DumpContext(ctx context.Context) string {
+	contextKeys.Lock()
+	keys := contextKeys.keys
+	contextKeys.Unlock()
+
+	var buf strings.Builder
+	for _, k := range keys {
+		if v, ok := k.lookup(ctx); ok {
+			buf.WriteString(fmt.Sprintf("%s = %v\n", k, v))
+		}
+	}
+	return buf.String()
+}
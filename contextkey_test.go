@@ -0,0 +1,45 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+var (
+	requestIDKey = util.NewContextKey[string]("requestID")
+	userKey      = util.NewContextKey[*Struct]("")
+	otherIDKey   = util.NewContextKey[string]("requestID")
+)
+
+func TestContextKey(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := requestIDKey.With(context.Background(), "abc")
+	v, ok := requestIDKey.Get(ctx)
+	a.True(ok)
+	a.Equal("abc", v)
+	a.Equal("abc", requestIDKey.MustGet(ctx))
+
+	_, ok = otherIDKey.Get(ctx)
+	a.False(ok, "keys with the same name are distinct")
+
+	user, ok := userKey.Get(ctx)
+	a.False(ok)
+	a.Nil(user)
+	a.Equal("*Struct", userKey.String())
+	a.PanicsWithValue("context key *Struct is not set", func() { userKey.MustGet(ctx) })
+}
+
+func TestDumpContext(t *testing.T) {
+	a := assert.New(t)
+
+	a.Empty(util.DumpContext(context.Background()))
+
+	ctx := requestIDKey.With(context.Background(), "abc")
+	ctx = userKey.With(ctx, &Struct{Value: "alice"})
+	a.Equal("requestID(string) = abc\n*Struct = &{alice}\n", util.DumpContext(ctx))
+}
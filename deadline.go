@@ -0,0 +1,104 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// Remaining returns the time left until the deadline of ctx.
+// ok is false if ctx has no deadline. The duration is negative once the deadline has passed.
+func Remaining(ctx context.Context) (d time.Duration, ok bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// WithMargin returns a child context whose deadline is margin earlier than the deadline of parent,
+// leaving the caller time to act on the result, e.g. to report a timeout.
+// If parent has no deadline, the child has none either.
+func WithMargin(parent context.Context, margin time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := parent.Deadline()
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, deadline.Add(-margin))
+}
+
+// WithMarginRatio is like WithMargin, but the margin is ratio (between 0 and 1) of the remaining time.
+//
+// For example:
+//
+//	WithMarginRatio(ctx with 10s left, 0.2) = child with 8s left
+func WithMarginRatio(parent context.Context, ratio float64) (context.Context, context.CancelFunc) {
+	remaining, ok := Remaining(parent)
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	return WithMargin(parent, time.Duration(float64(max(remaining, 0))*ratio))
+}
+
+// SplitDeadline splits the remaining time of parent between subtasks in proportion to weights,
+// returning one child context per weight.
+// Child i gets remaining*weights[i]/sum(weights), which suits subtasks run in parallel, e.g. a fan-out.
+// Negative weights count as zero; if all weights are zero, every child gets the deadline of parent.
+// If parent has no deadline, the children have none either.
+// The returned cancel function cancels all children.
+//
+// For example:
+//
+//	SplitDeadline(ctx with 10s left, 1, 3, 1) = children with 2s, 6s and 2s left
+func SplitDeadline(parent context.Context, weights ...float64) ([]context.Context, context.CancelFunc) {
+	return splitDeadline(parent, false, weights)
+}
+
+// SplitDeadlineSequential is like SplitDeadline, but the deadlines are cumulative:
+// child i ends when the shares of children 0..i have elapsed, and the last child ends at the deadline of parent.
+// This suits subtasks run one after another, as time left over by one is passed on to the next.
+//
+// For example:
+//
+//	SplitDeadlineSequential(ctx with 10s left, 1, 3, 1) = children with 2s, 8s and 10s left
+func SplitDeadlineSequential(parent context.Context, weights ...float64) ([]context.Context, context.CancelFunc) {
+	return splitDeadline(parent, true, weights)
+}
+
+func splitDeadline(parent context.Context, cumulative bool, weights []float64) ([]context.Context, context.CancelFunc) {
+	ctxs := make([]context.Context, len(weights))
+	cancels := make([]context.CancelFunc, len(weights))
+	cancel := func() {
+		for _, c := range cancels {
+			c()
+		}
+	}
+
+	deadline, ok := parent.Deadline()
+	if !ok {
+		for i := range weights {
+			ctxs[i], cancels[i] = context.WithCancel(parent)
+		}
+		return ctxs, cancel
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += max(w, 0)
+	}
+	now := time.Now()
+	remaining := max(deadline.Sub(now), 0)
+	var sum float64
+	for i, w := range weights {
+		share := max(w, 0)
+		if cumulative {
+			sum += share
+			share = sum
+		}
+		d := deadline
+		if total > 0 && !(cumulative && i == len(weights)-1) {
+			d = now.Add(time.Duration(float64(remaining) * share / total))
+		}
+		ctxs[i], cancels[i] = context.WithDeadline(parent, d)
+	}
+	return ctxs, cancel
+}
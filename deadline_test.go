@@ -0,0 +1,114 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestRemaining(t *testing.T) {
+	a := assert.New(t)
+
+	_, ok := util.Remaining(context.Background())
+	a.False(ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	d, ok := util.Remaining(ctx)
+	a.True(ok)
+	a.InDelta(time.Minute, d, float64(time.Second))
+}
+
+func TestWithMargin(t *testing.T) {
+	a := assert.New(t)
+
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ctx, cancel := util.WithMargin(parent, 2*time.Second)
+	defer cancel()
+	d, _ := util.Remaining(ctx)
+	a.InDelta(8*time.Second, d, float64(100*time.Millisecond))
+
+	ctx, cancel = util.WithMarginRatio(parent, 0.5)
+	defer cancel()
+	d, _ = util.Remaining(ctx)
+	a.InDelta(5*time.Second, d, float64(100*time.Millisecond))
+
+	ctx, cancel = util.WithMargin(context.Background(), time.Second)
+	defer cancel()
+	_, ok := util.Remaining(ctx)
+	a.False(ok)
+}
+
+func TestSplitDeadline(t *testing.T) {
+	a := assert.New(t)
+
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	parentDeadline, _ := parent.Deadline()
+
+	ctxs, cancelAll := util.SplitDeadline(parent, 1, 3, 1)
+	a.Len(ctxs, 3)
+	for i, want := range []time.Duration{2 * time.Second, 6 * time.Second, 2 * time.Second} {
+		d, ok := util.Remaining(ctxs[i])
+		a.True(ok)
+		a.InDelta(want, d, float64(100*time.Millisecond))
+	}
+
+	cancelAll()
+	for _, ctx := range ctxs {
+		a.ErrorIs(ctx.Err(), context.Canceled)
+	}
+
+	ctxs, cancelAll = util.SplitDeadline(parent, 1, 1)
+	for _, ctx := range ctxs {
+		d, _ := util.Remaining(ctx)
+		a.InDelta(5*time.Second, d, float64(100*time.Millisecond))
+	}
+	cancelAll()
+
+	ctxs, cancelAll = util.SplitDeadline(parent, 0, 0)
+	for _, ctx := range ctxs {
+		d, _ := ctx.Deadline()
+		a.Equal(parentDeadline, d)
+	}
+	cancelAll()
+
+	ctxs, cancelAll = util.SplitDeadline(context.Background(), 1, 1)
+	defer cancelAll()
+	_, ok := ctxs[0].Deadline()
+	a.False(ok)
+}
+
+func TestSplitDeadlineSequential(t *testing.T) {
+	a := assert.New(t)
+
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	parentDeadline, _ := parent.Deadline()
+
+	ctxs, cancelAll := util.SplitDeadlineSequential(parent, 1, 3, 1)
+	a.Len(ctxs, 3)
+	for i, want := range []time.Duration{2 * time.Second, 8 * time.Second, 10 * time.Second} {
+		d, ok := util.Remaining(ctxs[i])
+		a.True(ok)
+		a.InDelta(want, d, float64(100*time.Millisecond))
+	}
+	last, _ := ctxs[2].Deadline()
+	a.Equal(parentDeadline, last)
+
+	cancelAll()
+	for _, ctx := range ctxs {
+		a.ErrorIs(ctx.Err(), context.Canceled)
+	}
+
+	ctxs, cancelAll = util.SplitDeadlineSequential(context.Background(), 1, 1)
+	defer cancelAll()
+	_, ok := ctxs[0].Deadline()
+	a.False(ok)
+}
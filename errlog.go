@@ -0,0 +1,109 @@
+package util
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer. See ErrorLogValue.
+func (e *Error) LogValue() slog.Value {
+	return ErrorLogValue(e)
+}
+
+// ErrorLogValue returns err as a slog group with its message, its concrete type (GetTypeNameFromValue),
+// the code and fields of an *Error, and the errors it wraps:
+// a "cause" group for a single wrapped error, or a "causes" group keyed "0", "1", ... for joined errors.
+// It returns an empty value if err is nil.
+func ErrorLogValue(err error) slog.Value {
+	if err == nil {
+		return slog.Value{}
+	}
+	attrs := []slog.Attr{
+		slog.String("msg", err.Error()),
+		slog.String("type", GetTypeNameFromValue(err)),
+	}
+	if e, ok := err.(*Error); ok {
+		if e.Code != "" {
+			attrs = append(attrs, slog.String("code", e.Code))
+		}
+		if len(e.Fields) > 0 {
+			fields := make([]slog.Attr, len(e.Fields))
+			for i, f := range e.Fields {
+				fields[i] = slog.Any(f.Key, f.Value)
+			}
+			attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fields...)})
+		}
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: ErrorLogValue(cause)})
+		}
+	case interface{ Unwrap() []error }:
+		var causes []slog.Attr
+		for _, cause := range u.Unwrap() {
+			if cause != nil {
+				causes = append(causes, slog.Attr{Key: strconv.Itoa(len(causes)), Value: ErrorLogValue(cause)})
+			}
+		}
+		attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causes...)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ErrorHandler is a slog.Handler middleware that expands every error attribute with ErrorLogValue
+// before passing the record to the next handler.
+type ErrorHandler struct {
+	next slog.Handler
+}
+
+var _ slog.Handler = (*ErrorHandler)(nil)
+
+// NewErrorHandler returns an ErrorHandler passing records to next.
+func NewErrorHandler(next slog.Handler) *ErrorHandler {
+	return &ErrorHandler{next: next}
+}
+
+func (h *ErrorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *ErrorHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(expandErrorAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+func (h *ErrorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = expandErrorAttr(a)
+	}
+	return &ErrorHandler{next: h.next.WithAttrs(expanded)}
+}
+
+func (h *ErrorHandler) WithGroup(name string) slog.Handler {
+	return &ErrorHandler{next: h.next.WithGroup(name)}
+}
+
+// expandErrorAttr replaces error values in a, including inside groups, with ErrorLogValue.
+func expandErrorAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			a.Value = ErrorLogValue(err)
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, g := range group {
+			expanded[i] = expandErrorAttr(g)
+		}
+		a.Value = slog.GroupValue(expanded...)
+	}
+	return a
+}
@@ -0,0 +1,66 @@
+package util_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+// logJSON logs msg with args to a JSON handler wrapped by wrap and returns the output without time and level.
+func logJSON(wrap func(slog.Handler) slog.Handler, msg string, args ...any) string {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	slog.New(wrap(h)).Info(msg, args...)
+	return buf.String()
+}
+
+func TestErrorLogValue(t *testing.T) {
+	a := assert.New(t)
+
+	err := util.WithField(util.Wrap(errors.New("timeout"), "query"), "table", "users")
+	err = util.WithCode(err, "db_error")
+
+	out := logJSON(func(h slog.Handler) slog.Handler { return h }, "failed", "err", err)
+	a.JSONEq(`{"msg": "failed", "err": {
+		"msg": "query: timeout", "type": "*Error", "code": "db_error",
+		"fields": {"table": "users"},
+		"cause": {"msg": "timeout", "type": "*errorString"}
+	}}`, out)
+}
+
+func TestErrorHandler(t *testing.T) {
+	a := assert.New(t)
+
+	err := errors.Join(fmt.Errorf("item 1: %w", &MyError{Code: 1, Message: "bad"}), errors.New("item 2"))
+	wrap := func(h slog.Handler) slog.Handler { return util.NewErrorHandler(h) }
+
+	out := logJSON(wrap, "batch", slog.Group("result", "err", err), "count", 2)
+	a.JSONEq(`{"msg": "batch", "count": 2, "result": {"err": {
+		"msg": "item 1: Code 1: bad\nitem 2", "type": "*joinError",
+		"causes": {
+			"0": {"msg": "item 1: Code 1: bad", "type": "*wrapError", "cause": {"msg": "Code 1: bad", "type": "*MyError"}},
+			"1": {"msg": "item 2", "type": "*errorString"}
+		}
+	}}}`, out)
+
+	without := logJSON(func(h slog.Handler) slog.Handler { return h }, "batch", "err", errors.New("plain"))
+	a.JSONEq(`{"msg": "batch", "err": "plain"}`, without)
+
+	var buf bytes.Buffer
+	logger := slog.New(util.NewErrorHandler(slog.NewJSONHandler(&buf, nil))).With("err", errors.New("ctx")).WithGroup("g")
+	logger.Info("x")
+	a.Contains(buf.String(), `"err":{"msg":"ctx","type":"*errorString"}`)
+}
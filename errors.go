@@ -1,10 +1,262 @@
 package util
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"runtime"
+	"slices"
+)
 
 // ErrorAs checks if an error in err's chain matches target, and if so, sets target to the matching error.
 // It is a generic wrapper around errors.As.
 func ErrorAs[T error](err error) (asErr T, ok bool) {
 	ok = errors.As(err, &asErr)
 	return
-}
\ No newline at end of file
+}
+
+// ErrorsAs returns every error in err's tree that matches T, in the depth-first order of Walk.
+// Unlike ErrorAs, it does not stop at the first match, so it finds all matches in errors
+// combined with errors.Join or fmt.Errorf with several %w verbs.
+func ErrorsAs[T error](err error) []T {
+	var matches []T
+	for e := range Walk(err) {
+		if t, ok := e.(T); ok {
+			matches = append(matches, t)
+			continue
+		}
+		if as, ok := e.(interface{ As(any) bool }); ok {
+			var t T
+			if as.As(&t) {
+				matches = append(matches, t)
+			}
+		}
+	}
+	return matches
+}
+
+// Walk returns a sequence of err and every error it wraps, depth first:
+// each error is followed by the errors returned by its Unwrap() error or Unwrap() []error method.
+// It yields nothing if err is nil.
+func Walk(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		walkErrors(err, yield)
+	}
+}
+
+func walkErrors(err error, yield func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !yield(err) {
+		return false
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrors(u.Unwrap(), yield)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if !walkErrors(e, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ErrorIsAny reports whether any error in err's tree matches any of targets, as errors.Is does.
+func ErrorIsAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Error is a structured error carrying a machine-readable code, a message,
+// key/value fields, the stack where it was created, and the error it wraps.
+// Use NewError, Wrap, Wrapf, WithCode and WithField to build one.
+// The stack is captured by NewError, Wrap and Wrapf; use WithoutStack to drop it.
+type Error struct {
+	Code    string
+	Message string
+	Fields  []ErrorField
+	Cause   error
+	stack   []uintptr
+}
+
+// ErrorField is a key/value pair attached to an Error.
+type ErrorField struct {
+	Key   string
+	Value any
+}
+
+// NewError returns an Error with the given code and message, capturing the caller's stack.
+func NewError(code, message string) *Error {
+	return &Error{Code: code, Message: message, stack: callers(3)}
+}
+
+// Wrap returns an Error wrapping err with message, capturing the caller's stack.
+// It returns nil if err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Message: message, Cause: err, stack: callers(3)}
+}
+
+// Wrapf is like Wrap, but formats the message with fmt.Sprintf.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Message: fmt.Sprintf(format, args...), Cause: err, stack: callers(3)}
+}
+
+// WithField returns err with the field key=value added.
+// If err is an *Error, a copy with the field appended is returned; otherwise err is wrapped in a new Error.
+// It returns nil if err is nil.
+func WithField(err error, key string, value any) error {
+	if err == nil {
+		return nil
+	}
+	e := cloneError(err)
+	e.Fields = append(e.Fields, ErrorField{Key: key, Value: value})
+	return e
+}
+
+// WithCode returns err with its code set to code.
+// If err is an *Error, a copy with the code replaced is returned; otherwise err is wrapped in a new Error.
+// It returns nil if err is nil.
+func WithCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	e := cloneError(err)
+	e.Code = code
+	return e
+}
+
+// WithoutStack returns err with no captured stack, e.g. for sentinel errors
+// declared at package level or errors created on hot paths.
+// If err is an *Error, a copy without its stack is returned; otherwise err is wrapped in a new Error.
+// It returns nil if err is nil.
+func WithoutStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	e := cloneError(err)
+	e.stack = nil
+	return e
+}
+
+// cloneError returns a copy of err if it is an *Error, or a new Error wrapping it.
+func cloneError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		c := *e
+		c.Fields = slices.Clone(e.Fields)
+		return &c
+	}
+	return &Error{Cause: err}
+}
+
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+	return pcs[:n]
+}
+
+// Error returns the message followed by the message of the cause, e.g. "load config: file not found".
+func (e *Error) Error() string {
+	switch {
+	case e.Cause == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Cause.Error()
+	default:
+		return e.Message + ": " + e.Cause.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is an *Error with the same non-empty code,
+// so that errors.Is(err, NewError("not_found", "")) matches any error with code "not_found".
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// Stack returns the frames of the stack captured when the error was created,
+// or nil if it has none.
+func (e *Error) Stack() []runtime.Frame {
+	var stack []runtime.Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		if frame.PC != 0 {
+			stack = append(stack, frame)
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// Format implements fmt.Formatter.
+// %s and %v print Error(); %+v also prints the code, the fields and the stack.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.Error())
+			if e.Code != "" {
+				fmt.Fprintf(s, "\ncode: %s", e.Code)
+			}
+			for _, f := range e.Fields {
+				fmt.Fprintf(s, "\n%s: %v", f.Key, f.Value)
+			}
+			for _, frame := range e.Stack() {
+				fmt.Fprintf(s, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// ErrorCode returns the code of the first *Error in err's chain that has one, or "" if there is none.
+func ErrorCode(err error) string {
+	for err != nil {
+		if e, ok := ErrorAs[*Error](err); ok {
+			if e.Code != "" {
+				return e.Code
+			}
+			err = e.Cause
+			continue
+		}
+		return ""
+	}
+	return ""
+}
+
+// ErrorFields returns the fields of all *Error values in err's chain, outermost first.
+func ErrorFields(err error) []ErrorField {
+	var fields []ErrorField
+	for {
+		e, ok := ErrorAs[*Error](err)
+		if !ok {
+			return fields
+		}
+		fields = append(fields, e.Fields...)
+		err = e.Cause
+	}
+}
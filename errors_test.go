@@ -1,7 +1,10 @@
 package util_test
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/naycoma/util"
@@ -49,4 +52,127 @@ type AnotherError struct {
 
 func (e *AnotherError) Error() string {
 	return fmt.Sprintf("Reason: %s", e.Reason)
-}
\ No newline at end of file
+}
+
+func TestError(t *testing.T) {
+	a := assert.New(t)
+
+	errNotFound := errors.New("file not found")
+	err := util.Wrap(errNotFound, "load config")
+	err = util.WithField(err, "path", "/etc/app.yaml")
+	err = util.WithCode(err, "config_missing")
+	a.EqualError(err, "load config: file not found")
+	a.ErrorIs(err, errNotFound)
+	a.ErrorIs(err, util.NewError("config_missing", ""))
+	a.NotErrorIs(err, util.NewError("other", ""))
+	a.Equal("config_missing", util.ErrorCode(err))
+
+	asErr, ok := util.ErrorAs[*util.Error](err)
+	a.True(ok)
+	a.Equal("load config", asErr.Message)
+	a.Equal([]util.ErrorField{{Key: "path", Value: "/etc/app.yaml"}}, asErr.Fields)
+
+	outer := util.WithField(fmt.Errorf("startup: %w", err), "attempt", 2)
+	a.EqualError(outer, "startup: load config: file not found")
+	a.Equal([]util.ErrorField{{Key: "attempt", Value: 2}, {Key: "path", Value: "/etc/app.yaml"}}, util.ErrorFields(outer))
+	a.Equal("config_missing", util.ErrorCode(outer))
+
+	a.Nil(util.Wrap(nil, "x"))
+	a.Nil(util.Wrapf(nil, "x %d", 1))
+	a.Nil(util.WithField(nil, "k", "v"))
+	a.Nil(util.WithCode(nil, "c"))
+	a.Equal("", util.ErrorCode(errNotFound))
+}
+
+func TestErrorFormat(t *testing.T) {
+	a := assert.New(t)
+
+	err := util.WithField(util.NewError("invalid", "bad input"), "field", "name")
+	a.Equal("bad input", fmt.Sprintf("%v", err))
+	a.Equal("bad input", fmt.Sprintf("%s", err))
+	a.Equal(`"bad input"`, fmt.Sprintf("%q", err))
+
+	detailed := fmt.Sprintf("%+v", err)
+	a.True(strings.HasPrefix(detailed, "bad input\ncode: invalid\nfield: name\n"))
+	a.Contains(detailed, "util_test.TestErrorFormat")
+	a.Contains(detailed, "errors_test.go:")
+
+	wrapped := util.Wrapf(err, "request %d", 7)
+	a.EqualError(wrapped, "request 7: bad input")
+	e, _ := util.ErrorAs[*util.Error](wrapped)
+	a.Equal("util_test.TestErrorFormat", e.Stack()[0].Function[strings.LastIndex(e.Stack()[0].Function, "/")+1:])
+}
+
+func TestWithoutStack(t *testing.T) {
+	a := assert.New(t)
+
+	errFail := errors.New("fail")
+	err := util.WithoutStack(util.Wrap(errFail, "load"))
+	e, ok := util.ErrorAs[*util.Error](err)
+	a.True(ok)
+	a.Nil(e.Stack())
+	a.ErrorIs(err, errFail)
+	a.Equal("load: fail\ncode: none", fmt.Sprintf("%+v", util.WithCode(err, "none")))
+
+	plain, _ := util.ErrorAs[*util.Error](util.WithoutStack(errFail))
+	a.Nil(plain.Stack())
+	a.Equal("fail", plain.Error())
+
+	a.Nil(util.WithoutStack(nil))
+}
+
+func TestWalk(t *testing.T) {
+	a := assert.New(t)
+
+	e1 := errors.New("e1")
+	e2 := &MyError{Code: 2, Message: "e2"}
+	e3 := errors.New("e3")
+	tree := fmt.Errorf("root: %w", errors.Join(e1, fmt.Errorf("mid: %w %w", e2, e3)))
+
+	var msgs []string
+	for err := range util.Walk(tree) {
+		msgs = append(msgs, err.Error())
+	}
+	a.Equal([]string{
+		"root: e1\nmid: Code 2: e2 e3",
+		"e1\nmid: Code 2: e2 e3",
+		"e1",
+		"mid: Code 2: e2 e3",
+		"Code 2: e2",
+		"e3",
+	}, msgs)
+
+	var first []error
+	for err := range util.Walk(tree) {
+		first = append(first, err)
+		break
+	}
+	a.Equal([]error{tree}, first)
+	a.Empty(slices.Collect(util.Walk(nil)))
+}
+
+func TestErrorsAs(t *testing.T) {
+	a := assert.New(t)
+
+	v1 := &MyError{Code: 1}
+	v2 := &MyError{Code: 2}
+	v3 := &MyError{Code: 3}
+	err := errors.Join(v1, &AnotherError{}, fmt.Errorf("item: %w", v2), errors.Join(errors.New("x"), v3))
+	a.Equal([]*MyError{v1, v2, v3}, util.ErrorsAs[*MyError](err))
+	a.Len(util.ErrorsAs[*AnotherError](err), 1)
+	a.Empty(util.ErrorsAs[*MyError](errors.New("plain")))
+	a.Empty(util.ErrorsAs[*MyError](nil))
+}
+
+func TestErrorIsAny(t *testing.T) {
+	a := assert.New(t)
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errC := errors.New("c")
+	err := errors.Join(errA, fmt.Errorf("wrapped: %w", errB))
+	a.True(util.ErrorIsAny(err, errC, errB))
+	a.True(util.ErrorIsAny(err, errA))
+	a.False(util.ErrorIsAny(err, errC))
+	a.False(util.ErrorIsAny(err))
+}
@@ -0,0 +1,98 @@
+package util
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ErrorNode is one error in the tree built by ErrorTree.
+type ErrorNode struct {
+	// Message is the error's own message, without the messages of the errors it wraps
+	// when they only repeat them.
+	Message string `json:"message"`
+	// Type is the name of the error's concrete type, as returned by GetTypeNameFromValue.
+	Type     string       `json:"type"`
+	Children []*ErrorNode `json:"children,omitempty"`
+}
+
+// ErrorTree builds the tree of err and the errors it wraps. It returns nil if err is nil.
+//
+// Wrapper messages are collapsed: a wrapper whose message is "prefix: " followed by the message of
+// the error it wraps keeps only "prefix", and a wrapper or join whose message only repeats its children keeps none.
+func ErrorTree(err error) *ErrorNode {
+	if err == nil {
+		return nil
+	}
+	node := &ErrorNode{Message: err.Error(), Type: GetTypeNameFromValue(err)}
+	var children []error
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if child := u.Unwrap(); child != nil {
+			children = []error{child}
+		}
+	case interface{ Unwrap() []error }:
+		for _, child := range u.Unwrap() {
+			if child != nil {
+				children = append(children, child)
+			}
+		}
+	}
+	messages := make([]string, len(children))
+	for i, child := range children {
+		node.Children = append(node.Children, ErrorTree(child))
+		messages[i] = child.Error()
+	}
+	switch {
+	case len(children) == 0:
+	case node.Message == strings.Join(messages, "\n"):
+		node.Message = ""
+	case len(children) == 1 && strings.HasSuffix(node.Message, ": "+messages[0]):
+		node.Message = strings.TrimSuffix(node.Message, ": "+messages[0])
+	case len(children) == 1 && node.Message == messages[0]:
+		node.Message = ""
+	}
+	return node
+}
+
+// String renders the tree with one error per line, e.g.
+//
+//	load config (*Error)
+//	└─ open app.yaml (*PathError)
+//	   └─ no such file or directory (Errno)
+func (n *ErrorNode) String() string {
+	if n == nil {
+		return ""
+	}
+	var buf strings.Builder
+	n.write(&buf, "", "")
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (n *ErrorNode) write(buf *strings.Builder, first, rest string) {
+	label := "(" + n.Type + ")"
+	if n.Message != "" {
+		label = n.Message + " " + label
+	}
+	// Continuation lines of a multi-line message line up under the first line.
+	buf.WriteString(first + strings.ReplaceAll(label, "\n", "\n"+rest) + "\n")
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			child.write(buf, rest+"└─ ", rest+"   ")
+		} else {
+			child.write(buf, rest+"├─ ", rest+"│  ")
+		}
+	}
+}
+
+// FormatErrorTree renders err and the errors it wraps as an indented tree.
+// See ErrorTree for how messages are collapsed.
+func FormatErrorTree(err error) string {
+	return ErrorTree(err).String()
+}
+
+// FormatErrorTreeJSON renders err and the errors it wraps as JSON, for log shipping.
+// It returns "null" if err is nil.
+func FormatErrorTreeJSON(err error) string {
+	b, _ := json.Marshal(ErrorTree(err))
+	return string(b)
+}
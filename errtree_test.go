@@ -0,0 +1,57 @@
+package util_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestFormatErrorTree(t *testing.T) {
+	a := assert.New(t)
+
+	err := util.Wrap(errors.Join(
+		fmt.Errorf("item 1: %w", &MyError{Code: 400, Message: "bad price"}),
+		fmt.Errorf("item 2: %w", errors.Join(errors.New("no name"), &AnotherError{Reason: "gone"})),
+	), "validate order")
+
+	a.Equal(`validate order (*Error)
+└─ (*joinError)
+   ├─ item 1 (*wrapError)
+   │  └─ Code 400: bad price (*MyError)
+   └─ item 2 (*wrapError)
+      └─ (*joinError)
+         ├─ no name (*errorString)
+         └─ Reason: gone (*AnotherError)`, util.FormatErrorTree(err))
+
+	a.Equal("", util.FormatErrorTree(nil))
+	a.Equal("oops (*errorString)", util.FormatErrorTree(errors.New("oops")))
+
+	// Only a ": " separator is collapsed, exactly once.
+	a.Equal(`timeout (*wrapError)
+└─ out (*errorString)`, util.FormatErrorTree(fmt.Errorf("time%w", errors.New("out"))))
+	a.Equal(`retry:  (*wrapError)
+└─ x (*errorString)`, util.FormatErrorTree(fmt.Errorf("retry: : %w", errors.New("x"))))
+
+	multiline := fmt.Errorf("both: %w %w", errors.New("a"), errors.New("b\nc"))
+	a.Equal(`both: a b
+c (*wrapErrors)
+├─ a (*errorString)
+└─ b
+   c (*errorString)`, util.FormatErrorTree(multiline))
+}
+
+func TestFormatErrorTreeJSON(t *testing.T) {
+	a := assert.New(t)
+
+	err := fmt.Errorf("load: %w", errors.New("missing"))
+	a.JSONEq(`{
+		"message": "load",
+		"type": "*wrapError",
+		"children": [{"message": "missing", "type": "*errorString"}]
+	}`, util.FormatErrorTreeJSON(err))
+	a.Equal("null", util.FormatErrorTreeJSON(nil))
+}
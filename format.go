@@ -11,6 +11,7 @@ import (
 // StrFTime parses a string into a time.Time object.
 // It is equivalent to SQL's `strftime('%s', ?, 'utc')` for parsing.
 // It uses time.DateTime format and time.Local location.
+// It panics if str is malformed; use TryValue to get an error instead.
 //
 // For example:
 //
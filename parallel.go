@@ -0,0 +1,145 @@
+package util
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"iter"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// ParallelOptions configures ParallelMapWith.
+type ParallelOptions struct {
+	// Workers is the maximum number of concurrent calls. If it is not positive, runtime.GOMAXPROCS(0) is used.
+	Workers int
+	// Unordered yields results as soon as they are ready instead of in input order.
+	Unordered bool
+	// CollectErrors keeps going after a failed call and reports all errors joined with errors.Join.
+	// Otherwise the first error cancels the remaining calls.
+	CollectErrors bool
+}
+
+// ParallelMap is like Map, but calls iteratee concurrently on up to workers items.
+// Results are yielded in input order and the first error stops the mapping.
+// See ParallelMapWith for details.
+func ParallelMap[T, R any](ctx context.Context, seq iter.Seq[T], workers int, iteratee func(ctx context.Context, item T) (R, error)) (iter.Seq[R], func() error) {
+	return ParallelMapWith(ctx, seq, ParallelOptions{Workers: workers}, iteratee)
+}
+
+type parallelResult[R any] struct {
+	index int
+	value R
+	err   error
+}
+
+// ParallelMapWith calls iteratee concurrently on the items of seq and yields the results.
+// Items whose call failed are not yielded.
+// After the sequence ends, err reports the first error, all errors if opts.CollectErrors is set,
+// or the context's error if ctx was done before all items were mapped.
+//
+// The calls run while the sequence is being iterated, and at most 2*Workers items are in flight,
+// which also bounds the reorder buffer in ordered mode.
+// All goroutines have exited by the time the iteration returns, including when the caller breaks early.
+func ParallelMapWith[T, R any](ctx context.Context, seq iter.Seq[T], opts ParallelOptions, iteratee func(ctx context.Context, item T) (R, error)) (iter.Seq[R], func() error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var seqErr error
+	mapped := func(yield func(R) bool) {
+		seqErr = nil
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type job struct {
+			index int
+			item  T
+		}
+		window := make(chan struct{}, 2*workers)
+		jobs := make(chan job)
+		results := make(chan parallelResult[R])
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+			index := 0
+			for item := range seq {
+				if SendContext(ctx, window, struct{}{}) != nil {
+					return
+				}
+				if SendContext(ctx, jobs, job{index, item}) != nil {
+					return
+				}
+				index++
+			}
+		}()
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					value, err := iteratee(ctx, j.item)
+					if SendContext(ctx, results, parallelResult[R]{j.index, value, err}) != nil {
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+		defer func() {
+			cancel()
+			for range results {
+			}
+		}()
+
+		var errs []parallelResult[R]
+		defer func() {
+			if len(errs) == 0 {
+				seqErr = ctx.Err()
+				return
+			}
+			slices.SortFunc(errs, func(a, b parallelResult[R]) int { return cmp.Compare(a.index, b.index) })
+			seqErr = errors.Join(slices.Collect(Map(slices.Values(errs), func(r parallelResult[R]) error { return r.err }))...)
+		}()
+		// emit handles a result in yield order and reports whether to continue.
+		emit := func(r parallelResult[R]) bool {
+			<-window
+			if r.err != nil {
+				errs = append(errs, r)
+				return opts.CollectErrors
+			}
+			return yield(r.value)
+		}
+
+		pending := map[int]parallelResult[R]{}
+		next := 0
+		for r := range results {
+			if opts.Unordered {
+				if !emit(r) {
+					return
+				}
+				continue
+			}
+			pending[r.index] = r
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				if !emit(r) {
+					return
+				}
+			}
+		}
+	}
+	return mapped, func() error { return seqErr }
+}
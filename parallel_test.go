@@ -0,0 +1,100 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestParallelMap(t *testing.T) {
+	a := assert.New(t)
+
+	var running, peak atomic.Int32
+	square := func(ctx context.Context, v int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		// Later items finish first to exercise the reorder buffer.
+		time.Sleep(time.Duration(20-v) * time.Millisecond / 10)
+		return v * v, nil
+	}
+	seq, errFn := util.ParallelMap(context.Background(), util.Range[int](20), 4, square)
+	got := slices.Collect(seq)
+	a.NoError(errFn())
+	a.Equal(slices.Collect(util.Map(util.Range[int](20), func(v int) int { return v * v })), got)
+	a.LessOrEqual(peak.Load(), int32(4))
+}
+
+func TestParallelMapUnordered(t *testing.T) {
+	a := assert.New(t)
+
+	seq, errFn := util.ParallelMapWith(context.Background(), util.Range[int](20), util.ParallelOptions{Workers: 3, Unordered: true},
+		func(ctx context.Context, v int) (int, error) { return v * 2, nil })
+	got := slices.Collect(seq)
+	a.NoError(errFn())
+	slices.Sort(got)
+	a.Equal(slices.Collect(util.RangeWithSteps(0, 40, 2)), got)
+}
+
+func TestParallelMapErrors(t *testing.T) {
+	a := assert.New(t)
+
+	failOdd := func(ctx context.Context, v int) (int, error) {
+		if v%2 == 1 {
+			return 0, fmt.Errorf("odd %d", v)
+		}
+		return v, nil
+	}
+
+	seq, errFn := util.ParallelMap(context.Background(), util.Range[int](10), 2, failOdd)
+	got := slices.Collect(seq)
+	a.Error(errFn())
+	a.NotContains(got, 1)
+
+	seq, errFn = util.ParallelMapWith(context.Background(), util.Range[int](6), util.ParallelOptions{Workers: 2, CollectErrors: true}, failOdd)
+	a.Equal([]int{0, 2, 4}, slices.Collect(seq))
+	a.EqualError(errFn(), "odd 1\nodd 3\nodd 5")
+}
+
+func TestParallelMapCancel(t *testing.T) {
+	a := assert.New(t)
+
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	block := func(ctx context.Context, v int) (int, error) {
+		if v == 3 {
+			cancel()
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	seq, errFn := util.ParallelMapWith(ctx, util.Range[int](100), util.ParallelOptions{Workers: 4, CollectErrors: true}, block)
+	a.Empty(slices.Collect(seq))
+	a.True(errors.Is(errFn(), context.Canceled))
+
+	seq, _ = util.ParallelMap(context.Background(), util.Range[int](100), 4, func(ctx context.Context, v int) (int, error) { return v, nil })
+	for v := range seq {
+		if v == 2 {
+			break
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	a.LessOrEqual(runtime.NumGoroutine(), before)
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"io/fs"
+	"iter"
 	"os"
 	"path/filepath"
 )
@@ -9,23 +11,124 @@ import (
 // If the file is found, it returns the absolute path and true.
 // If the file is not found up to the root directory, it returns an empty string and false.
 func FindUp(file string) (path string, ok bool) {
-	currentDir, err := os.Getwd()
+	return FindUpFrom("", FindUpOptions{Names: []string{file}})
+}
+
+// FindUpType restricts what kind of entry FindUpFrom matches.
+type FindUpType int
+
+const (
+	FindAny FindUpType = iota
+	FindFile
+	FindDir
+)
+
+// FindUpOptions configures FindUpFrom and FindUpAll.
+type FindUpOptions struct {
+	// Names are the candidate entry names, checked in order in each directory.
+	Names []string
+	// Match, if not nil, is called for every entry of each directory, in lexical order,
+	// after the entries in Names.
+	Match func(path string, entry fs.DirEntry) bool
+	// Type restricts matches to files or directories. Symbolic links are followed.
+	Type FindUpType
+	// StopAt, if not nil, is called for each directory after it has been searched;
+	// returning true ends the search there. The search always ends at the filesystem root.
+	// See StopAtDir, StopAtHome and StopAtGitRoot.
+	StopAt func(dir string) bool
+}
+
+// StopAtDir returns a FindUpOptions.StopAt function that ends the search at dir.
+func StopAtDir(dir string) func(dir string) bool {
+	stop, err := filepath.Abs(dir)
 	if err != nil {
-		return
+		stop = filepath.Clean(dir)
 	}
+	return func(dir string) bool { return dir == stop }
+}
+
+// StopAtHome returns a FindUpOptions.StopAt function that ends the search at the user's home directory.
+// If the home directory is unknown, the search ends at the filesystem root.
+func StopAtHome() func(dir string) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return func(string) bool { return false }
+	}
+	return StopAtDir(home)
+}
+
+// StopAtGitRoot is a FindUpOptions.StopAt function that ends the search at a directory containing .git.
+func StopAtGitRoot(dir string) bool {
+	return IsExist(filepath.Join(dir, ".git"))
+}
 
-	for {
-		path = filepath.Join(currentDir, file)
-		ok = IsExist(path)
-		if ok {
+// FindUpFrom searches for an entry by walking up parent directories from start, or from the current directory
+// if start is empty, and returns the absolute path of the nearest match.
+// If nothing matches before the search ends, it returns an empty string and false.
+func FindUpFrom(start string, opts FindUpOptions) (path string, ok bool) {
+	for path := range FindUpAll(start, opts) {
+		return path, true
+	}
+	return "", false
+}
+
+// FindUpAll is like FindUpFrom, but yields every match from nearest to farthest.
+//
+// For example, in a monorepo:
+//
+//	FindUpAll("repo/services/api", FindUpOptions{Names: []string{"go.mod"}, StopAt: StopAtGitRoot})
+//	yields repo/services/api/go.mod and then repo/go.mod
+func FindUpAll(start string, opts FindUpOptions) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		dir, err := findUpStart(start)
+		if err != nil {
 			return
 		}
-		parent := filepath.Dir(currentDir)
-		// root
-		if currentDir == parent {
-			return
+		for {
+			for _, name := range opts.Names {
+				path := filepath.Join(dir, name)
+				if findUpTypeMatches(path, opts.Type) && !yield(path) {
+					return
+				}
+			}
+			if opts.Match != nil {
+				entries, _ := os.ReadDir(dir)
+				for _, entry := range entries {
+					path := filepath.Join(dir, entry.Name())
+					if opts.Match(path, entry) && findUpTypeMatches(path, opts.Type) && !yield(path) {
+						return
+					}
+				}
+			}
+			parent := filepath.Dir(dir)
+			// root
+			if dir == parent || (opts.StopAt != nil && opts.StopAt(dir)) {
+				return
+			}
+			dir = parent
 		}
-		currentDir = parent
+	}
+}
+
+func findUpStart(start string) (string, error) {
+	if start == "" {
+		return os.Getwd()
+	}
+	return filepath.Abs(start)
+}
+
+func findUpTypeMatches(path string, typ FindUpType) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	switch typ {
+	case FindFile:
+		return !info.IsDir()
+	case FindDir:
+		return info.IsDir()
+	default:
+		return true
 	}
 }
 
@@ -38,6 +141,9 @@ func IsExist(file string) bool {
 // ProjectRoot returns the absolute path of the project root directory by finding the go.mod file.
 // It returns an empty string if go.mod is not found.
 func ProjectRoot() string {
-	mod, _ := FindUp("go.mod")
+	mod, ok := FindUp("go.mod")
+	if !ok {
+		return ""
+	}
 	return filepath.Dir(mod)
 }
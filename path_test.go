@@ -0,0 +1,110 @@
+package util_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+// makeTree creates the given files (or directories, if they end with "/") under a temporary directory
+// and returns its path.
+func makeTree(t *testing.T, paths ...string) string {
+	root := t.TempDir()
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		if strings.HasSuffix(p, "/") {
+			assert.NoError(t, os.MkdirAll(full, 0o755))
+			continue
+		}
+		assert.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		assert.NoError(t, os.WriteFile(full, nil, 0o644))
+	}
+	return root
+}
+
+func TestFindUp(t *testing.T) {
+	a := assert.New(t)
+
+	path, ok := util.FindUp("go.mod")
+	a.True(ok)
+	a.Equal(filepath.Join(util.ProjectRoot(), "go.mod"), path)
+
+	path, ok = util.FindUp("no-such-file-anywhere.txt")
+	a.False(ok)
+	a.Empty(path)
+
+	t.Chdir(t.TempDir())
+	if _, ok := util.FindUp("go.mod"); !ok {
+		a.Empty(util.ProjectRoot())
+	}
+}
+
+func TestFindUpFrom(t *testing.T) {
+	a := assert.New(t)
+
+	root := makeTree(t,
+		"go.mod",
+		".git/",
+		"services/api/go.mod",
+		"services/api/internal/handler/",
+		"services/config/",
+		"services/api/internal/.env",
+	)
+	start := filepath.Join(root, "services/api/internal/handler")
+
+	path, ok := util.FindUpFrom(start, util.FindUpOptions{Names: []string{"go.mod"}})
+	a.True(ok)
+	a.Equal(filepath.Join(root, "services/api/go.mod"), path)
+
+	path, ok = util.FindUpFrom(start, util.FindUpOptions{Names: []string{"go.work", ".env", "go.mod"}})
+	a.True(ok)
+	a.Equal(filepath.Join(root, "services/api/internal/.env"), path)
+
+	path, ok = util.FindUpFrom(start, util.FindUpOptions{Names: []string{"config"}, Type: util.FindDir})
+	a.True(ok)
+	a.Equal(filepath.Join(root, "services/config"), path)
+	_, ok = util.FindUpFrom(start, util.FindUpOptions{Names: []string{"config"}, Type: util.FindFile, StopAt: util.StopAtGitRoot})
+	a.False(ok)
+
+	_, ok = util.FindUpFrom(start, util.FindUpOptions{Names: []string{"go.mod"}, StopAt: util.StopAtDir(filepath.Join(root, "services/api/internal"))})
+	a.False(ok)
+
+	path, ok = util.FindUpFrom(start, util.FindUpOptions{
+		Match: func(path string, entry fs.DirEntry) bool { return strings.HasPrefix(entry.Name(), ".") },
+	})
+	a.True(ok)
+	a.Equal(filepath.Join(root, "services/api/internal/.env"), path)
+}
+
+func TestFindUpAll(t *testing.T) {
+	a := assert.New(t)
+
+	root := makeTree(t,
+		"go.mod",
+		".git/",
+		"services/go.mod",
+		"services/api/go.mod",
+		"services/api/cmd/",
+	)
+	start := filepath.Join(root, "services/api/cmd")
+	opts := util.FindUpOptions{Names: []string{"go.mod"}, Type: util.FindFile, StopAt: util.StopAtGitRoot}
+	a.Equal([]string{
+		filepath.Join(root, "services/api/go.mod"),
+		filepath.Join(root, "services/go.mod"),
+		filepath.Join(root, "go.mod"),
+	}, slices.Collect(util.FindUpAll(start, opts)))
+
+	var first []string
+	for path := range util.FindUpAll(start, opts) {
+		first = append(first, path)
+		break
+	}
+	a.Len(first, 1)
+}
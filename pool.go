@@ -0,0 +1,171 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrPoolClosed = errors.New("pool closed")
+
+// Pool runs submitted tasks on a resizable number of workers.
+// A panicking task is recovered and reported as a *PanicError.
+type Pool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	wg      sync.WaitGroup
+	queue   []func(ctx context.Context) error
+	target  int
+	workers int
+	closed  bool
+	errs    []error
+	stats   PoolStats
+}
+
+// PoolStats is a snapshot of a Pool's state.
+type PoolStats struct {
+	Workers   int
+	QueueLen  int
+	InFlight  int
+	Completed int
+	Failed    int
+	// LastLatency, AvgLatency and MaxLatency measure how long finished tasks took to run.
+	LastLatency time.Duration
+	AvgLatency  time.Duration
+	MaxLatency  time.Duration
+
+	totalLatency time.Duration
+}
+
+// String formats the stats on one line, with latencies formatted by FormatDuration.
+func (s PoolStats) String() string {
+	return fmt.Sprintf("workers=%d queued=%d inflight=%d completed=%d failed=%d latency(last=%s avg=%s max=%s)",
+		s.Workers, s.QueueLen, s.InFlight, s.Completed, s.Failed,
+		FormatDuration(s.LastLatency), FormatDuration(s.AvgLatency), FormatDuration(s.MaxLatency))
+}
+
+// NewPool returns a Pool with the given number of workers.
+// Tasks receive a context that is canceled by Stop.
+func NewPool(workers int) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{ctx: ctx, cancel: cancel}
+	p.cond = sync.NewCond(&p.mu)
+	p.Resize(workers)
+	return p
+}
+
+// Submit queues task. It returns ErrPoolClosed if Close or Stop was called.
+func (p *Pool) Submit(task func(ctx context.Context) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.queue = append(p.queue, task)
+	p.cond.Signal()
+	return nil
+}
+
+// Resize changes the number of workers to n, at least 1.
+// Surplus workers exit after finishing their current task.
+func (p *Pool) Resize(n int) {
+	n = max(n, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.target = n
+	for p.workers < p.target {
+		p.workers++
+		p.wg.Add(1)
+		go p.work()
+	}
+	p.cond.Broadcast()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		for len(p.queue) == 0 && !p.closed && p.workers <= p.target {
+			p.cond.Wait()
+		}
+		if p.workers > p.target || len(p.queue) == 0 {
+			p.workers--
+			return
+		}
+		task := p.queue[0]
+		p.queue[0] = nil
+		p.queue = p.queue[1:]
+		p.stats.InFlight++
+		p.mu.Unlock()
+
+		start := time.Now()
+		err := p.run(task)
+		latency := time.Since(start)
+
+		p.mu.Lock()
+		p.stats.InFlight--
+		p.stats.Completed++
+		p.stats.LastLatency = latency
+		p.stats.totalLatency += latency
+		p.stats.MaxLatency = max(p.stats.MaxLatency, latency)
+		if err != nil {
+			p.stats.Failed++
+			p.errs = append(p.errs, err)
+		}
+	}
+}
+
+func (p *Pool) run(task func(ctx context.Context) error) (err error) {
+	if panicErr := Try(func() { err = task(p.ctx) }); panicErr != nil {
+		return panicErr
+	}
+	return err
+}
+
+// Close stops accepting tasks, waits for the queued and running tasks to finish,
+// and returns the errors of all failed tasks joined with errors.Join.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	p.wg.Wait()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Join(p.errs...)
+}
+
+// Stop stops accepting tasks, discards the queued tasks, cancels the context of the running tasks
+// and waits for them to return. It returns the number of discarded tasks.
+func (p *Pool) Stop() int {
+	p.mu.Lock()
+	p.closed = true
+	discarded := len(p.queue)
+	p.queue = nil
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	p.cancel()
+	p.wg.Wait()
+	return discarded
+}
+
+// Stats returns a snapshot of the pool's state.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Workers = p.workers
+	stats.QueueLen = len(p.queue)
+	if stats.Completed > 0 {
+		stats.AvgLatency = stats.totalLatency / time.Duration(stats.Completed)
+	}
+	return stats
+}
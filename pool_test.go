@@ -0,0 +1,100 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestPool(t *testing.T) {
+	a := assert.New(t)
+
+	p := util.NewPool(3)
+	var count atomic.Int32
+	for i := range 10 {
+		a.NoError(p.Submit(func(ctx context.Context) error {
+			count.Add(1)
+			if i == 4 {
+				return errors.New("task 4 failed")
+			}
+			if i == 7 {
+				panic("boom")
+			}
+			return nil
+		}))
+	}
+	err := p.Close()
+	a.Equal(int32(10), count.Load())
+	a.ErrorContains(err, "task 4 failed")
+	panicErr, ok := util.ErrorAs[*util.PanicError](err)
+	a.True(ok)
+	a.Equal("boom", panicErr.Value)
+	a.ErrorIs(p.Submit(func(ctx context.Context) error { return nil }), util.ErrPoolClosed)
+
+	stats := p.Stats()
+	a.Equal(10, stats.Completed)
+	a.Equal(2, stats.Failed)
+	a.Equal(0, stats.Workers)
+	a.True(strings.HasPrefix(stats.String(), "workers=0 queued=0 inflight=0 completed=10 failed=2 latency(last=00:00:00"))
+}
+
+func TestPoolResize(t *testing.T) {
+	a := assert.New(t)
+
+	p := util.NewPool(1)
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+	for range 4 {
+		a.NoError(p.Submit(func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}))
+	}
+	<-started
+	stats := p.Stats()
+	a.Equal(1, stats.InFlight)
+	a.Equal(3, stats.QueueLen)
+
+	p.Resize(4)
+	for range 3 {
+		<-started
+	}
+	a.Equal(4, p.Stats().InFlight)
+
+	p.Resize(2)
+	close(release)
+	a.NoError(p.Close())
+}
+
+func TestPoolStop(t *testing.T) {
+	a := assert.New(t)
+
+	p := util.NewPool(1)
+	started := make(chan struct{})
+	a.NoError(p.Submit(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	for range 5 {
+		a.NoError(p.Submit(func(ctx context.Context) error { return nil }))
+	}
+	<-started
+	done := make(chan int)
+	go func() { done <- p.Stop() }()
+	select {
+	case discarded := <-done:
+		a.Equal(5, discarded)
+	case <-time.After(time.Second):
+		a.Fail("Stop did not cancel the running task")
+	}
+	a.Equal(1, p.Stats().Completed)
+}
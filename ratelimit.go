@@ -0,0 +1,265 @@
+package util
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Limiter limits how often events may happen.
+type Limiter interface {
+	// Allow reports whether an event may happen now, and consumes it if so.
+	Allow() bool
+	// Wait blocks until an event may happen and consumes it.
+	// If the context is done first, it returns the context's error and the event is given back.
+	Wait(ctx context.Context) error
+	// Reserve consumes an event and returns how long the caller must wait before acting on it.
+	Reserve() time.Duration
+}
+
+// waitReserved waits for a reservation made by reserve, undoing it with cancel if ctx is done first.
+func waitReserved(ctx context.Context, clock Clocker, reserve func() (time.Duration, func())) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	delay, cancel := reserve()
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	case <-clock.After(delay):
+		return nil
+	}
+}
+
+// TokenBucket is a Limiter that refills rate tokens per second up to burst tokens.
+// It is safe for concurrent use.
+type TokenBucket struct {
+	mu     sync.Mutex
+	clock  Clocker
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+var _ Limiter = (*TokenBucket)(nil)
+
+// NewTokenBucket returns a full TokenBucket allowing rate events per second with bursts of up to burst events.
+// If clock is nil, SystemClock is used.
+// It panics if rate is not positive or burst is less than 1.
+func NewTokenBucket(rate float64, burst int, clock Clocker) *TokenBucket {
+	if !(rate > 0) || burst < 1 {
+		panic("NewTokenBucket: rate must be positive and burst at least 1")
+	}
+	clock = clockOrSystem(clock)
+	return &TokenBucket{
+		clock:  clock,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last call. It must be called with mu held.
+func (b *TokenBucket) refill() {
+	now := b.clock.Now()
+	if now.After(b.last) {
+		b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.burst)
+		b.last = now
+	}
+}
+
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *TokenBucket) Reserve() time.Duration {
+	delay, _ := b.reserve()
+	return delay
+}
+
+func (b *TokenBucket) reserve() (time.Duration, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.tokens--
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.tokens = min(b.tokens+1, b.burst)
+	}
+	if b.tokens >= 0 {
+		return 0, cancel
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), cancel
+}
+
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return waitReserved(ctx, b.clock, b.reserve)
+}
+
+// SlidingWindow is a Limiter that allows at most limit events in any window of the given length,
+// keeping a log of event times.
+// It is safe for concurrent use.
+type SlidingWindow struct {
+	mu     sync.Mutex
+	clock  Clocker
+	limit  int
+	window time.Duration
+	log    []time.Time
+}
+
+var _ Limiter = (*SlidingWindow)(nil)
+
+// NewSlidingWindow returns a SlidingWindow allowing limit events per window.
+// If clock is nil, SystemClock is used.
+// It panics if limit is less than 1 or window is not positive.
+func NewSlidingWindow(limit int, window time.Duration, clock Clocker) *SlidingWindow {
+	if limit < 1 || window <= 0 {
+		panic("NewSlidingWindow: limit must be at least 1 and window positive")
+	}
+	return &SlidingWindow{
+		clock:  clockOrSystem(clock),
+		limit:  limit,
+		window: window,
+	}
+}
+
+// prune drops the events that left the window and returns the current time. It must be called with mu held.
+func (w *SlidingWindow) prune() time.Time {
+	now := w.clock.Now()
+	start := now.Add(-w.window)
+	i := 0
+	for i < len(w.log) && !w.log[i].After(start) {
+		i++
+	}
+	w.log = w.log[i:]
+	return now
+}
+
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.prune()
+	if len(w.log) >= w.limit {
+		return false
+	}
+	w.log = append(w.log, now)
+	return true
+}
+
+func (w *SlidingWindow) Reserve() time.Duration {
+	delay, _ := w.reserve()
+	return delay
+}
+
+func (w *SlidingWindow) reserve() (time.Duration, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.prune()
+	at := now
+	if len(w.log) >= w.limit {
+		at = MaxTime(now, w.log[len(w.log)-w.limit].Add(w.window))
+	}
+	w.log = append(w.log, at)
+	cancel := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if i := slices.Index(w.log, at); i >= 0 {
+			w.log = slices.Delete(w.log, i, i+1)
+		}
+	}
+	return at.Sub(now), cancel
+}
+
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	return waitReserved(ctx, w.clock, w.reserve)
+}
+
+// KeyedLimiter keeps a separate Limiter per key, such as one per user.
+// Limiters that have not been used for the idle duration are evicted.
+// It is safe for concurrent use.
+type KeyedLimiter[K comparable] struct {
+	mu        sync.Mutex
+	clock     Clocker
+	newFn     func() Limiter
+	idle      time.Duration
+	limiters  map[K]*keyedEntry
+	lastSweep time.Time
+}
+
+type keyedEntry struct {
+	limiter  Limiter
+	lastUsed time.Time
+}
+
+// NewKeyedLimiter returns a KeyedLimiter that creates limiters with newLimiter and evicts them after idle.
+// If clock is nil, SystemClock is used.
+// It panics if newLimiter is nil or idle is not positive.
+func NewKeyedLimiter[K comparable](newLimiter func() Limiter, idle time.Duration, clock Clocker) *KeyedLimiter[K] {
+	if newLimiter == nil || idle <= 0 {
+		panic("NewKeyedLimiter: newLimiter must not be nil and idle must be positive")
+	}
+	clock = clockOrSystem(clock)
+	return &KeyedLimiter[K]{
+		clock:     clock,
+		newFn:     newLimiter,
+		idle:      idle,
+		limiters:  map[K]*keyedEntry{},
+		lastSweep: clock.Now(),
+	}
+}
+
+// Limiter returns the Limiter for key, creating it if needed.
+func (l *KeyedLimiter[K]) Limiter(key K) Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	if now.Sub(l.lastSweep) >= l.idle {
+		for k, e := range l.limiters {
+			if now.Sub(e.lastUsed) >= l.idle {
+				delete(l.limiters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	e, ok := l.limiters[key]
+	if !ok {
+		e = &keyedEntry{limiter: l.newFn()}
+		l.limiters[key] = e
+	}
+	e.lastUsed = now
+	return e.limiter
+}
+
+// Len returns the number of keys currently tracked.
+func (l *KeyedLimiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.limiters)
+}
+
+func (l *KeyedLimiter[K]) Allow(key K) bool {
+	return l.Limiter(key).Allow()
+}
+
+func (l *KeyedLimiter[K]) Wait(ctx context.Context, key K) error {
+	return l.Limiter(key).Wait(ctx)
+}
+
+func (l *KeyedLimiter[K]) Reserve(key K) time.Duration {
+	return l.Limiter(key).Reserve()
+}
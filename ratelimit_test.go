@@ -0,0 +1,101 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestTokenBucket(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newStepClock(time.Now())
+	b := util.NewTokenBucket(2, 3, clock)
+	a.True(b.Allow())
+	a.True(b.Allow())
+	a.True(b.Allow())
+	a.False(b.Allow())
+
+	clock.Advance(500 * time.Millisecond)
+	a.True(b.Allow())
+	a.False(b.Allow())
+
+	a.Equal(500*time.Millisecond, b.Reserve())
+	a.Equal(time.Second, b.Reserve())
+
+	a.NoError(b.Wait(context.Background()))
+	a.Equal([]time.Duration{1500 * time.Millisecond}, clock.sleeps)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newStepClock(time.Now())
+	w := util.NewSlidingWindow(2, time.Minute, clock)
+	a.True(w.Allow())
+	clock.Advance(10 * time.Second)
+	a.True(w.Allow())
+	a.False(w.Allow())
+
+	a.Equal(50*time.Second, w.Reserve())
+	clock.Advance(50 * time.Second)
+	a.False(w.Allow())
+	a.Equal(10*time.Second, w.Reserve())
+
+	a.NoError(w.Wait(context.Background()))
+	a.Equal([]time.Duration{time.Minute}, clock.sleeps)
+}
+
+func TestLimiterWaitCancel(t *testing.T) {
+	a := assert.New(t)
+
+	for l, interval := range map[util.Limiter]time.Duration{
+		util.NewTokenBucket(1, 1, nil):           time.Second,
+		util.NewSlidingWindow(1, time.Hour, nil): time.Hour,
+	} {
+		a.True(l.Allow())
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		a.ErrorIs(l.Wait(ctx), context.DeadlineExceeded)
+		cancel()
+		// The cancelled reservation was given back.
+		a.LessOrEqual(l.Reserve(), interval)
+	}
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newStepClock(time.Now())
+	l := util.NewKeyedLimiter[string](func() util.Limiter {
+		return util.NewSlidingWindow(1, time.Minute, clock)
+	}, 10*time.Minute, clock)
+
+	a.True(l.Allow("alice"))
+	a.False(l.Allow("alice"))
+	a.True(l.Allow("bob"))
+	a.Equal(2, l.Len())
+
+	clock.Advance(5 * time.Minute)
+	a.True(l.Allow("alice"))
+	clock.Advance(6 * time.Minute)
+	a.True(l.Allow("carol"))
+	a.Equal(2, l.Len(), "bob was idle and evicted")
+}
+
+func TestLimiterInvalidArguments(t *testing.T) {
+	a := assert.New(t)
+
+	a.Panics(func() { util.NewTokenBucket(0, 1, nil) })
+	a.Panics(func() { util.NewTokenBucket(-1, 1, nil) })
+	a.Panics(func() { util.NewTokenBucket(1, 0, nil) })
+	a.Panics(func() { util.NewSlidingWindow(0, time.Second, nil) })
+	a.Panics(func() { util.NewSlidingWindow(1, 0, nil) })
+	newLimiter := func() util.Limiter { return util.NewTokenBucket(1, 1, nil) }
+	a.Panics(func() { util.NewKeyedLimiter[string](newLimiter, 0, nil) })
+	a.Panics(func() { util.NewKeyedLimiter[string](newLimiter, -time.Second, nil) })
+	a.Panics(func() { util.NewKeyedLimiter[string](nil, time.Second, nil) })
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack of the panicking goroutine, as returned by debug.Stack.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error, so that errors.Is and ErrorAs see through the panic.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Try calls fn and returns a *PanicError if it panics.
+//
+// For example:
+//
+//	err := Try(func() { StrFTime(input) })
+func Try(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	fn()
+	return nil
+}
+
+// TryValue calls fn and returns its value, or a *PanicError if it panics.
+//
+// For example:
+//
+//	t, err := TryValue(func() time.Time { return StrFTime(input) })
+func TryValue[T any](fn func() T) (v T, err error) {
+	err = Try(func() { v = fn() })
+	return
+}
+
+// Go runs fn in a new goroutine. If fn returns an error or panics, handler is called with the error,
+// or with a *PanicError, instead of the panic crashing the process.
+// If handler is nil, errors and panics are discarded.
+// The returned channel is closed when fn and handler have returned.
+func Go(ctx context.Context, fn func(ctx context.Context) error, handler func(err error)) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var fnErr error
+		if err := Try(func() { fnErr = fn(ctx) }); err != nil {
+			fnErr = err
+		}
+		if fnErr != nil && handler != nil {
+			handler(fnErr)
+		}
+	}()
+	return done
+}
@@ -0,0 +1,60 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestTry(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(util.Try(func() {}))
+
+	err := util.Try(func() { panic("boom") })
+	panicErr, ok := util.ErrorAs[*util.PanicError](err)
+	a.True(ok)
+	a.Equal("boom", panicErr.Value)
+	a.EqualError(err, "panic: boom")
+	a.Contains(string(panicErr.Stack), "recover_test.go")
+
+	errCause := errors.New("cause")
+	a.ErrorIs(util.Try(func() { panic(errCause) }), errCause)
+}
+
+func TestTryValue(t *testing.T) {
+	a := assert.New(t)
+
+	v, err := util.TryValue(func() time.Time { return util.StrFTime("2006-01-02 15:04:05") })
+	a.NoError(err)
+	a.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.Local), v)
+
+	v, err = util.TryValue(func() time.Time { return util.StrFTime("not a time") })
+	a.ErrorContains(err, "cannot parse")
+	a.True(v.IsZero())
+}
+
+func TestGo(t *testing.T) {
+	a := assert.New(t)
+
+	errs := make(chan error, 1)
+	handler := func(err error) { errs <- err }
+
+	<-util.Go(context.Background(), func(ctx context.Context) error { panic("boom") }, handler)
+	_, ok := util.ErrorAs[*util.PanicError](<-errs)
+	a.True(ok)
+
+	<-util.Go(context.Background(), func(ctx context.Context) error { return errors.New("failed") }, handler)
+	a.EqualError(<-errs, "failed")
+
+	<-util.Go(context.Background(), func(ctx context.Context) error { return nil }, handler)
+	_, err := util.TryRecv(errs)
+	a.ErrorIs(err, util.ErrChEmpty)
+
+	<-util.Go(context.Background(), func(ctx context.Context) error { panic("ignored") }, nil)
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package util
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultReloadSignals are the signals that request a configuration reload.
+var DefaultReloadSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1}
+
+// ReloadEvent is a reload request delivered by ReloadSignals.
+type ReloadEvent struct {
+	// Signal is the last signal received.
+	Signal os.Signal
+	// Count is the number of signals coalesced into this event.
+	Count int
+	// Time is when the last signal was received.
+	Time time.Time
+}
+
+// ReloadSignals returns a channel of reload events for the given signals.
+// If no signals are given, DefaultReloadSignals is used.
+// Signals received while an event is waiting to be received are coalesced into that event,
+// so a burst of signals results in a single reload.
+// Signals are captured from the moment ReloadSignals returns; the channel is closed when ctx is done.
+func ReloadSignals(ctx context.Context, signals ...os.Signal) <-chan ReloadEvent {
+	if len(signals) == 0 {
+		signals = DefaultReloadSignals
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, signals...)
+	out := make(chan ReloadEvent)
+	go func() {
+		defer close(out)
+		defer signal.Stop(sigCh)
+		var pending ReloadEvent
+		for {
+			// send is nil, and never ready, until there is a pending event.
+			var send chan<- ReloadEvent
+			if pending.Count > 0 {
+				send = out
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sigCh:
+				pending = ReloadEvent{Signal: sig, Count: pending.Count + 1, Time: time.Now()}
+			case send <- pending:
+				pending = ReloadEvent{}
+			}
+		}
+	}()
+	return out
+}
+
+// ReloadLoop calls reload for every event from ReloadSignals with DefaultReloadSignals until ctx is done,
+// and then returns the context's error.
+// Reloads never overlap: signals received during a reload are coalesced into one more reload afterwards.
+// If reload fails and onError is not nil, onError is called with the error.
+func ReloadLoop(ctx context.Context, reload func(ctx context.Context) error, onError func(err error)) error {
+	for range ReloadSignals(ctx) {
+		if err := reload(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,74 @@
+//go:build unix
+
+package util_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestReloadSignals(t *testing.T) {
+	a := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := util.ReloadSignals(ctx, syscall.SIGHUP)
+
+	for range 3 {
+		a.NoError(syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	ev, err := util.RecvTimeout(context.Background(), events, time.Second)
+	a.NoError(err)
+	a.Equal(syscall.SIGHUP, ev.Signal)
+	a.GreaterOrEqual(ev.Count, 1)
+	_, err = util.TryRecv(events)
+	a.ErrorIs(err, util.ErrChEmpty, "the burst was coalesced")
+
+	cancel()
+	_, err = util.DrainContext(context.Background(), events)
+	a.NoError(err)
+}
+
+func TestReloadLoop(t *testing.T) {
+	a := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloaded := make(chan struct{}, 1)
+	errs := make(chan error, 1)
+	done := make(chan error)
+	go func() {
+		done <- util.ReloadLoop(ctx, func(ctx context.Context) error {
+			_ = util.TrySend(reloaded, struct{}{})
+			return errors.New("bad config")
+		}, func(err error) { _ = util.TrySend(errs, err) })
+	}()
+
+	// Catch SIGHUP here too, so that a signal sent before the loop listens does not terminate the test binary.
+	keep := make(chan os.Signal, 1)
+	signal.Notify(keep, syscall.SIGHUP)
+	defer signal.Stop(keep)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for sent := false; !sent; {
+		a.NoError(syscall.Kill(os.Getpid(), syscall.SIGHUP))
+		select {
+		case <-reloaded:
+			sent = true
+		case <-ticker.C:
+		}
+	}
+	a.EqualError(<-errs, "bad config")
+
+	cancel()
+	a.ErrorIs(<-done, context.Canceled)
+}
@@ -0,0 +1,179 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay before the next attempt.
+// attempt is the number of attempts made so far (starting at 1) and prev is the previous delay (0 at first).
+type Backoff func(attempt int, prev time.Duration) time.Duration
+
+// ConstantBackoff waits d between attempts.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration { return d }
+}
+
+// ExponentialBackoff waits base, base*factor, base*factor^2, ... between attempts.
+func ExponentialBackoff(base time.Duration, factor float64) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := float64(base) * math.Pow(factor, float64(attempt-1))
+		if d >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(d)
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random delay between base and three times the previous delay,
+// capped at limit.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func DecorrelatedJitterBackoff(base, limit time.Duration) Backoff {
+	return func(_ int, prev time.Duration) time.Duration {
+		upper := min(max(3*prev, base), limit)
+		if upper <= base {
+			return upper
+		}
+		return base + rand.N(upper-base)
+	}
+}
+
+// CappedBackoff limits the delays of b to limit.
+func CappedBackoff(b Backoff, limit time.Duration) Backoff {
+	return func(attempt int, prev time.Duration) time.Duration {
+		return min(b(attempt, prev), limit)
+	}
+}
+
+// DefaultBackoff is the Backoff used when RetryPolicy.Backoff is nil:
+// 100ms doubling on each attempt, capped at 10s.
+var DefaultBackoff = CappedBackoff(ExponentialBackoff(100*time.Millisecond, 2), 10*time.Second)
+
+// RetryPolicy configures Retry.
+type RetryPolicy struct {
+	// Backoff returns the delay between attempts. If nil, DefaultBackoff is used,
+	// so that the zero RetryPolicy does not retry in a tight loop.
+	// Use ConstantBackoff(0) to retry without delay.
+	Backoff Backoff
+	// MaxAttempts limits the number of attempts. If it is not positive, the number is unlimited.
+	MaxAttempts int
+	// MaxElapsed stops retrying once the next attempt would start after MaxElapsed since the first one.
+	// If it is not positive, the time is unlimited.
+	MaxElapsed time.Duration
+	// Retryable reports whether err should be retried. If nil, every error is retried.
+	// Errors wrapped with Permanent are never retried.
+	Retryable func(err error) bool
+	// Clock is used for delays. If nil, SystemClock is used.
+	Clock Clocker
+}
+
+// RetryError is returned by Retry when it gives up.
+// Err is the last error, joined with the context's error if the context ended the retries.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// PermanentError marks an error that must not be retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that Retry stops immediately. It returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// RetryOn returns a RetryPolicy.Retryable predicate that retries errors whose chain contains a T.
+func RetryOn[T error]() func(err error) bool {
+	return func(err error) bool {
+		_, ok := ErrorAs[T](err)
+		return ok
+	}
+}
+
+// RetryUnless returns a RetryPolicy.Retryable predicate that retries errors whose chain does not contain a T.
+func RetryUnless[T error]() func(err error) bool {
+	return func(err error) bool {
+		_, ok := ErrorAs[T](err)
+		return !ok
+	}
+}
+
+// Retry calls fn until it succeeds, returns a non-retryable error, or policy gives up.
+// Delays between attempts end early when ctx is done.
+// If fn never succeeds, Retry returns a *RetryError carrying the last error and the number of attempts.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	_, err := RetryValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// RetryValue is like Retry, but returns the value of the successful call.
+func RetryValue[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	clock := clockOrSystem(policy.Clock)
+	backoff := policy.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+	start := clock.Now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+		giveUp := func(err error) (T, error) {
+			var zero T
+			return zero, &RetryError{Attempts: attempt, Err: err}
+		}
+		if _, ok := ErrorAs[*PermanentError](err); ok {
+			return giveUp(err)
+		}
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return giveUp(err)
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return giveUp(err)
+		}
+		delay = backoff(attempt, delay)
+		if policy.MaxElapsed > 0 && clock.Now().Add(delay).Sub(start) > policy.MaxElapsed {
+			return giveUp(err)
+		}
+		if ctx.Err() != nil {
+			return giveUp(errors.Join(err, ctx.Err()))
+		}
+		if delay > 0 {
+			select {
+			case <-ctx.Done():
+				return giveUp(errors.Join(err, ctx.Err()))
+			case <-clock.After(delay):
+			}
+		}
+	}
+}
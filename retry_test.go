@@ -0,0 +1,153 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+// stepClock is a FakeClock whose After advances the time immediately.
+type stepClock struct {
+	*util.FakeClock
+	sleeps []time.Duration
+}
+
+func newStepClock(now time.Time) *stepClock {
+	return &stepClock{FakeClock: util.NewFakeClock(now)}
+}
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.sleeps = append(c.sleeps, d)
+	ch := c.FakeClock.After(d)
+	c.Advance(d)
+	return ch
+}
+
+func TestBackoff(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(time.Second, util.ConstantBackoff(time.Second)(5, 0))
+
+	exp := util.ExponentialBackoff(100*time.Millisecond, 2)
+	a.Equal(100*time.Millisecond, exp(1, 0))
+	a.Equal(800*time.Millisecond, exp(4, 0))
+	a.Equal(300*time.Millisecond, util.CappedBackoff(exp, 300*time.Millisecond)(4, 0))
+
+	jitter := util.DecorrelatedJitterBackoff(100*time.Millisecond, time.Second)
+	var prev time.Duration
+	for attempt := 1; attempt <= 20; attempt++ {
+		d := jitter(attempt, prev)
+		a.GreaterOrEqual(d, 100*time.Millisecond)
+		a.LessOrEqual(d, time.Second)
+		a.LessOrEqual(d, max(3*prev, 100*time.Millisecond))
+		prev = d
+	}
+}
+
+func TestRetry(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newStepClock(time.Now())
+	calls := 0
+	err := util.Retry(context.Background(), util.RetryPolicy{
+		Backoff:     util.ExponentialBackoff(time.Second, 2),
+		MaxAttempts: 5,
+		Clock:       clock,
+	}, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+	a.NoError(err)
+	a.Equal(3, calls)
+	a.Equal([]time.Duration{time.Second, 2 * time.Second}, clock.sleeps)
+
+	errFail := errors.New("fail")
+	clock = newStepClock(time.Now())
+	err = util.Retry(context.Background(), util.RetryPolicy{MaxAttempts: 4, Clock: clock}, func(ctx context.Context) error {
+		return errFail
+	})
+	retryErr, ok := util.ErrorAs[*util.RetryError](err)
+	a.True(ok)
+	a.Equal(4, retryErr.Attempts)
+	a.ErrorIs(err, errFail)
+	a.Equal([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, clock.sleeps,
+		"a nil Backoff uses DefaultBackoff")
+}
+
+func TestRetryClassification(t *testing.T) {
+	a := assert.New(t)
+
+	calls := 0
+	err := util.Retry(context.Background(), util.RetryPolicy{}, func(ctx context.Context) error {
+		calls++
+		return util.Permanent(errors.New("bad request"))
+	})
+	a.Equal(1, calls)
+	a.EqualError(err, "after 1 attempts: bad request")
+
+	calls = 0
+	err = util.Retry(context.Background(), util.RetryPolicy{Retryable: util.RetryOn[*MyError](), Clock: newStepClock(time.Now())}, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return &MyError{Code: 503}
+		}
+		return &AnotherError{Reason: "gone"}
+	})
+	a.Equal(3, calls)
+	_, ok := util.ErrorAs[*AnotherError](err)
+	a.True(ok)
+
+	calls = 0
+	_ = util.Retry(context.Background(), util.RetryPolicy{Retryable: util.RetryUnless[*AnotherError]()}, func(ctx context.Context) error {
+		calls++
+		return &AnotherError{}
+	})
+	a.Equal(1, calls)
+}
+
+func TestRetryLimits(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newStepClock(time.Now())
+	calls := 0
+	err := util.Retry(context.Background(), util.RetryPolicy{
+		Backoff:    util.ConstantBackoff(time.Minute),
+		MaxElapsed: 5 * time.Minute,
+		Clock:      clock,
+	}, func(ctx context.Context) error {
+		calls++
+		return errors.New("down")
+	})
+	a.Error(err)
+	a.Equal(6, calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = util.Retry(ctx, util.RetryPolicy{Backoff: util.ConstantBackoff(time.Hour)}, func(ctx context.Context) error {
+		return errors.New("down")
+	})
+	a.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func TestRetryValue(t *testing.T) {
+	a := assert.New(t)
+
+	calls := 0
+	v, err := util.RetryValue(context.Background(), util.RetryPolicy{Backoff: util.ConstantBackoff(0)}, func(ctx context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("temporary")
+		}
+		return "ok", nil
+	})
+	a.NoError(err)
+	a.Equal("ok", v)
+}
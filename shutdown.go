@@ -0,0 +1,185 @@
+package util
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ShutdownSignals are the signals that request a graceful shutdown.
+// os.Kill is not included because it cannot be caught.
+var ShutdownSignals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+
+// ForceExitCode is the exit code used when a second signal forces an immediate exit.
+const ForceExitCode = 1
+
+// Shutdown runs registered hooks when the process receives a shutdown signal.
+// The first signal runs the hooks in priority order; a second signal received
+// while the hooks are running forces an immediate exit.
+type Shutdown struct {
+	// Exit is called with ForceExitCode on the second signal.
+	// It defaults to os.Exit.
+	Exit func(code int)
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+	sigCh chan os.Signal
+}
+
+type shutdownHook struct {
+	name     string
+	priority int
+	timeout  time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// NewShutdown returns a Shutdown listening for the given signals.
+// If no signals are given, ShutdownSignals is used.
+// Signals are captured from the moment NewShutdown returns until Stop is called.
+func NewShutdown(signals ...os.Signal) *Shutdown {
+	if len(signals) == 0 {
+		signals = ShutdownSignals
+	}
+	s := &Shutdown{
+		Exit:  os.Exit,
+		sigCh: make(chan os.Signal, 2),
+	}
+	signal.Notify(s.sigCh, signals...)
+	return s
+}
+
+// Register adds a hook named name.
+// Hooks with a higher priority run first; hooks with the same priority run in registration order.
+// If timeout is positive, the hook's context is canceled after timeout and the hook is
+// reported as timed out, even if fn does not return.
+func (s *Shutdown) Register(name string, priority int, timeout time.Duration, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, priority: priority, timeout: timeout, fn: fn})
+}
+
+// Stop stops listening for signals. It does not run any hooks.
+func (s *Shutdown) Stop() {
+	signal.Stop(s.sigCh)
+}
+
+// Wait blocks until a signal is received or ctx is done, then runs the hooks.
+// A second signal received while the hooks are running calls Exit(ForceExitCode).
+// The hooks are not canceled by ctx.
+func (s *Shutdown) Wait(ctx context.Context) *ShutdownReport {
+	var sig os.Signal
+	select {
+	case sig = <-s.sigCh:
+	case <-ctx.Done():
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.sigCh:
+			s.Exit(ForceExitCode)
+		case <-done:
+		}
+	}()
+
+	report := s.Run(context.WithoutCancel(ctx))
+	report.Signal = sig
+	return report
+}
+
+// Run runs the hooks immediately, in priority order.
+func (s *Shutdown) Run(ctx context.Context) *ShutdownReport {
+	s.mu.Lock()
+	hooks := slices.Clone(s.hooks)
+	s.mu.Unlock()
+	slices.SortStableFunc(hooks, func(a, b shutdownHook) int {
+		return cmp.Compare(b.priority, a.priority)
+	})
+
+	report := &ShutdownReport{}
+	for _, hook := range hooks {
+		report.Results = append(report.Results, hook.run(ctx))
+	}
+	return report
+}
+
+func (h shutdownHook) run(parent context.Context) ShutdownResult {
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if h.timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, h.timeout)
+	}
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() { errCh <- h.fn(ctx) }()
+
+	result := ShutdownResult{Name: h.name}
+	select {
+	case result.Err = <-errCh:
+	case <-ctx.Done():
+		result.Err = ctx.Err()
+		// Only the hook's own timeout counts; a canceled or expired parent is a plain failure.
+		result.TimedOut = h.timeout > 0 && parent.Err() == nil && errors.Is(result.Err, context.DeadlineExceeded)
+	}
+	result.Elapsed = time.Since(start)
+	return result
+}
+
+// ShutdownResult is the outcome of a single hook.
+type ShutdownResult struct {
+	Name string
+	Err  error
+	// TimedOut reports whether the hook exceeded its own timeout.
+	TimedOut bool
+	Elapsed  time.Duration
+}
+
+// ShutdownReport is the outcome of running the hooks.
+type ShutdownReport struct {
+	// Signal is the signal that triggered the shutdown, or nil if it was not triggered by a signal.
+	Signal  os.Signal
+	Results []ShutdownResult
+}
+
+// TimedOut returns the names of the hooks that exceeded their own timeout.
+func (r *ShutdownReport) TimedOut() []string {
+	var names []string
+	for _, result := range r.Results {
+		if result.TimedOut {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+// Failed returns the names of the hooks that returned an error or timed out.
+func (r *ShutdownReport) Failed() []string {
+	var names []string
+	for _, result := range r.Results {
+		if result.Err != nil {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+// Err returns the errors of all failed hooks joined with errors.Join,
+// each prefixed with the hook name. It returns nil if all hooks succeeded.
+func (r *ShutdownReport) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
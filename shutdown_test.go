@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestShutdownRun(t *testing.T) {
+	a := assert.New(t)
+
+	s := util.NewShutdown(syscall.SIGTERM)
+	defer s.Stop()
+
+	var order []string
+	hook := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	s.Register("db", 0, 0, hook("db", nil))
+	s.Register("http", 10, 0, hook("http", nil))
+	s.Register("cache", 0, 0, hook("cache", errors.New("flush failed")))
+	// slow overruns its timeout, and exits once the test is done.
+	release := make(chan struct{})
+	defer close(release)
+	s.Register("slow", -1, time.Millisecond, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	report := s.Run(context.Background())
+	a.Equal([]string{"http", "db", "cache"}, order)
+	a.Equal([]string{"slow"}, report.TimedOut())
+	a.Equal([]string{"cache", "slow"}, report.Failed())
+	a.ErrorIs(report.Err(), context.DeadlineExceeded)
+	a.ErrorContains(report.Err(), "cache: flush failed")
+	a.Nil(report.Signal)
+}
+
+func TestShutdownRunCanceled(t *testing.T) {
+	a := assert.New(t)
+
+	s := util.NewShutdown(syscall.SIGTERM)
+	defer s.Stop()
+	s.Register("wait", 0, 0, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Register("timed", 0, time.Hour, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := s.Run(ctx)
+	a.Empty(report.TimedOut())
+	a.Equal([]string{"wait", "timed"}, report.Failed())
+	a.ErrorIs(report.Err(), context.Canceled)
+}
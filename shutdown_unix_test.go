@@ -0,0 +1,51 @@
+//go:build unix
+
+package util_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestShutdownWait(t *testing.T) {
+	a := assert.New(t)
+
+	s := util.NewShutdown(syscall.SIGUSR2)
+	defer s.Stop()
+	exited := make(chan int, 1)
+	s.Exit = func(code int) { exited <- code }
+
+	release := make(chan struct{})
+	s.Register("block", 0, 0, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	done := make(chan *util.ShutdownReport)
+	go func() { done <- s.Wait(context.Background()) }()
+
+	p, err := os.FindProcess(os.Getpid())
+	a.NoError(err)
+	a.NoError(p.Signal(syscall.SIGUSR2))
+	time.Sleep(10 * time.Millisecond)
+	a.NoError(p.Signal(syscall.SIGUSR2))
+
+	select {
+	case code := <-exited:
+		a.Equal(util.ForceExitCode, code)
+	case <-time.After(time.Second):
+		a.Fail("second signal did not force exit")
+	}
+
+	close(release)
+	report := <-done
+	a.Equal(syscall.SIGUSR2, report.Signal)
+	a.NoError(report.Err())
+}
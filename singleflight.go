@@ -0,0 +1,218 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Group deduplicates concurrent calls that share a key.
+// The zero value is ready to use.
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+type flightCall[V any] struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	val     V
+	err     error
+}
+
+// Do calls fn once for all concurrent callers with the same key and returns its result to each of them.
+// shared reports whether the result was given to more than one caller.
+//
+// fn runs in its own goroutine with a context that is not canceled by any single caller.
+// A caller whose ctx is done stops waiting and gets the context's error;
+// once every caller has stopped waiting, fn's context is canceled.
+func (g *Group[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (v V, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[K]*flightCall[V]{}
+	}
+	c, ok := g.calls[key]
+	if ok {
+		c.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &flightCall[V]{done: make(chan struct{}), cancel: cancel, waiters: 1}
+		g.calls[key] = c
+		go func() {
+			defer cancel()
+			c.val, c.err = fn(callCtx)
+			g.mu.Lock()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			close(c.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared = c.waiters > 1
+		g.mu.Unlock()
+		return c.val, shared, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return v, false, ctx.Err()
+	}
+}
+
+// Forget makes the next call to Do for key start a new call instead of waiting for the one in flight.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
+
+// Memo caches the results of a function per key, deduplicating concurrent calls with a Group.
+type Memo[K comparable, V any] struct {
+	fn     func(ctx context.Context, key K) (V, error)
+	ttl    time.Duration
+	errTTL time.Duration
+	clock  Clocker
+	group  Group[K, V]
+
+	mu        sync.Mutex
+	entries   map[K]memoEntry[V]
+	lastSweep time.Time
+	// flights tracks the calls to fn in flight per key, so that a call started before Forget
+	// does not store its result afterwards. A key is removed once it has no calls in flight.
+	flights map[K]*memoFlight
+}
+
+type memoEntry[V any] struct {
+	val     V
+	err     error
+	expires time.Time
+}
+
+type memoFlight struct {
+	calls      int
+	generation uint64
+}
+
+// Memoize returns a Memo calling fn.
+// Results are cached for ttl, or forever if ttl is not positive.
+// Errors are cached for errTTL, or not at all if errTTL is not positive;
+// context errors are never cached.
+// Expired results are swept periodically by Get. Results cached forever are only dropped by Forget,
+// so with a non-positive ttl the cache grows with the number of keys.
+// If clock is nil, SystemClock is used.
+func Memoize[K comparable, V any](fn func(ctx context.Context, key K) (V, error), ttl, errTTL time.Duration, clock Clocker) *Memo[K, V] {
+	clock = clockOrSystem(clock)
+	return &Memo[K, V]{
+		fn:        fn,
+		ttl:       ttl,
+		errTTL:    errTTL,
+		clock:     clock,
+		entries:   map[K]memoEntry[V]{},
+		lastSweep: clock.Now(),
+		flights:   map[K]*memoFlight{},
+	}
+}
+
+// Get returns the cached result for key, calling fn if there is none or it has expired.
+func (m *Memo[K, V]) Get(ctx context.Context, key K) (V, error) {
+	m.mu.Lock()
+	now := m.clock.Now()
+	m.sweep(now)
+	e, ok := m.entries[key]
+	if ok && (e.expires.IsZero() || now.Before(e.expires)) {
+		m.mu.Unlock()
+		return e.val, e.err
+	}
+	delete(m.entries, key)
+	m.mu.Unlock()
+
+	v, _, err := m.group.Do(ctx, key, func(ctx context.Context) (V, error) {
+		m.mu.Lock()
+		f, ok := m.flights[key]
+		if !ok {
+			f = &memoFlight{}
+			m.flights[key] = f
+		}
+		f.calls++
+		generation := f.generation
+		m.mu.Unlock()
+		v, err := m.fn(ctx, key)
+		m.store(key, f, generation, v, err)
+		return v, err
+	})
+	return v, err
+}
+
+// sweep drops expired results, at most once per the shorter of ttl and errTTL.
+func (m *Memo[K, V]) sweep(now time.Time) {
+	interval := m.ttl
+	if m.errTTL > 0 && (interval <= 0 || m.errTTL < interval) {
+		interval = m.errTTL
+	}
+	if interval <= 0 || now.Sub(m.lastSweep) < interval {
+		return
+	}
+	for k, e := range m.entries {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.lastSweep = now
+}
+
+// store ends the call f started at generation and caches its result, unless Forget has been called since.
+func (m *Memo[K, V]) store(key K, f *memoFlight, generation uint64, v V, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f.calls--; f.calls == 0 {
+		delete(m.flights, key)
+	}
+	if f.generation != generation {
+		return
+	}
+	ttl := m.ttl
+	if err != nil {
+		if m.errTTL <= 0 || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		ttl = m.errTTL
+	}
+	e := memoEntry[V]{val: v, err: err}
+	if ttl > 0 {
+		e.expires = m.clock.Now().Add(ttl)
+	}
+	m.entries[key] = e
+}
+
+// Forget drops the cached result for key, so the next Get calls fn again.
+// A call for key that is in flight is not cached when it finishes.
+func (m *Memo[K, V]) Forget(key K) {
+	m.mu.Lock()
+	delete(m.entries, key)
+	if f, ok := m.flights[key]; ok {
+		f.generation++
+	}
+	m.mu.Unlock()
+	m.group.Forget(key)
+}
+
+// Len returns the number of cached results, including expired ones not yet swept.
+func (m *Memo[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
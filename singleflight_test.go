@@ -0,0 +1,177 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestGroup(t *testing.T) {
+	a := assert.New(t)
+
+	var g util.Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	shared := make([]bool, 5)
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], shared[i], _ = g.Do(context.Background(), "key", fn)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	a.Equal(int32(1), calls.Load())
+	a.Equal([]int{42, 42, 42, 42, 42}, results)
+	a.Equal([]bool{true, true, true, true, true}, shared)
+}
+
+func TestGroupWaiterGivesUp(t *testing.T) {
+	a := assert.New(t)
+
+	var g util.Group[string, int]
+	release := make(chan struct{})
+	canceled := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			close(canceled)
+			return 0, ctx.Err()
+		}
+	}
+
+	impatient, cancel := context.WithCancel(context.Background())
+	patient := make(chan int)
+	go func() {
+		v, _, _ := g.Do(context.Background(), "key", fn)
+		patient <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, _, err := g.Do(impatient, "key", fn)
+	a.ErrorIs(err, context.Canceled)
+	close(release)
+	a.Equal(1, <-patient, "other waiters are not affected")
+
+	// When every waiter gives up, the call is canceled.
+	release = make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = g.Do(ctx, "other", fn)
+	a.ErrorIs(err, context.DeadlineExceeded)
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		a.Fail("call was not canceled")
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	a := assert.New(t)
+
+	clock := newStepClock(time.Now())
+	calls := map[string]int{}
+	errNotFound := errors.New("not found")
+	m := util.Memoize(func(ctx context.Context, key string) (string, error) {
+		calls[key]++
+		if key == "missing" {
+			return "", errNotFound
+		}
+		return "value of " + key, nil
+	}, time.Minute, 10*time.Second, clock)
+
+	ctx := context.Background()
+	for range 3 {
+		v, err := m.Get(ctx, "a")
+		a.NoError(err)
+		a.Equal("value of a", v)
+		_, err = m.Get(ctx, "missing")
+		a.ErrorIs(err, errNotFound)
+	}
+	a.Equal(map[string]int{"a": 1, "missing": 1}, calls)
+
+	clock.Advance(30 * time.Second)
+	_, _ = m.Get(ctx, "a")
+	_, _ = m.Get(ctx, "missing")
+	a.Equal(map[string]int{"a": 1, "missing": 2}, calls)
+
+	clock.Advance(31 * time.Second)
+	_, _ = m.Get(ctx, "a")
+	a.Equal(2, calls["a"])
+
+	m.Forget("a")
+	_, _ = m.Get(ctx, "a")
+	a.Equal(3, calls["a"])
+}
+
+func TestMemoizeForgetInFlight(t *testing.T) {
+	a := assert.New(t)
+
+	var calls atomic.Int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	m := util.Memoize(func(ctx context.Context, key string) (int32, error) {
+		n := calls.Add(1)
+		started <- struct{}{}
+		<-release
+		return n, nil
+	}, 0, 0, nil)
+
+	done := make(chan int32)
+	go func() {
+		v, _ := m.Get(context.Background(), "k")
+		done <- v
+	}()
+	<-started
+	m.Forget("k")
+	close(release)
+	a.Equal(int32(1), <-done)
+
+	v, err := m.Get(context.Background(), "k")
+	a.NoError(err)
+	a.Equal(int32(2), v, "the result forgotten mid-flight was not cached")
+	v, _ = m.Get(context.Background(), "k")
+	a.Equal(int32(2), v)
+}
+
+func TestMemoizeSweep(t *testing.T) {
+	a := assert.New(t)
+
+	clock := util.NewFakeClock(time.Now())
+	m := util.Memoize(func(ctx context.Context, key string) (string, error) {
+		return key, nil
+	}, time.Minute, 0, clock)
+
+	ctx := context.Background()
+	for _, key := range []string{"a", "b", "c"} {
+		_, _ = m.Get(ctx, key)
+	}
+	m.Forget("never cached")
+	a.Equal(3, m.Len())
+
+	clock.Advance(2 * time.Minute)
+	_, _ = m.Get(ctx, "d")
+	a.Equal(1, m.Len(), "expired results of other keys were swept")
+}
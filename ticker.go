@@ -0,0 +1,58 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// alignedTickerMaxSleep bounds each wait of an aligned ticker, so that a wall-clock jump
+// or a system sleep is noticed within this time.
+const alignedTickerMaxSleep = time.Minute
+
+// AlignedTicker returns a channel that receives the wall-clock boundaries of interval plus offset
+// in the local timezone (see NextBoundary) as they are reached.
+// For example, AlignedTicker(ctx, 15*time.Minute, 0) fires at :00, :15, :30 and :45.
+//
+// The value received is the scheduled boundary, not the actual wakeup time.
+// If the receiver falls behind or the system sleeps through boundaries, the missed ones are skipped
+// and only the latest is delivered.
+// The channel is closed when ctx is done.
+// It panics if interval is not positive.
+func AlignedTicker(ctx context.Context, interval, offset time.Duration) <-chan time.Time {
+	return AlignedTickerIn(ctx, time.Local, interval, offset, nil)
+}
+
+// AlignedTickerIn is like AlignedTicker, but aligns to boundaries in loc and reads the time from clock.
+// If clock is nil, SystemClock is used.
+func AlignedTickerIn(ctx context.Context, loc *time.Location, interval, offset time.Duration, clock Clocker) <-chan time.Time {
+	if interval <= 0 {
+		panic("non-positive interval for AlignedTicker")
+	}
+	clock = clockOrSystem(clock)
+	out := make(chan time.Time)
+	go func() {
+		defer close(out)
+		// next has no monotonic reading, so comparisons with it follow the wall clock.
+		next := NextBoundary(clock.Now().In(loc), interval, offset)
+		for {
+			now := clock.Now().In(loc)
+			if now.Before(next) {
+				select {
+				case <-ctx.Done():
+					return
+				case <-clock.After(min(next.Sub(now), alignedTickerMaxSleep)):
+				}
+				continue
+			}
+			latest := next
+			for b := NextBoundary(latest, interval, offset); !now.Before(b); b = NextBoundary(b, interval, offset) {
+				latest = b
+			}
+			if SendContext(ctx, out, latest) != nil {
+				return
+			}
+			next = NextBoundary(latest, interval, offset)
+		}
+	}()
+	return out
+}
@@ -0,0 +1,63 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+// jumpClock is a stepClock whose next After also jumps by jump, like a system sleep.
+type jumpClock struct {
+	*stepClock
+	jump time.Duration
+}
+
+func (c *jumpClock) After(d time.Duration) <-chan time.Time {
+	c.Advance(c.jump)
+	c.jump = 0
+	return c.stepClock.After(d)
+}
+
+func TestAlignedTicker(t *testing.T) {
+	a := assert.New(t)
+
+	jst := date(2025, 8, 8).Location()
+	at := func(hour, min int) time.Time { return time.Date(2025, 8, 8, hour, min, 0, 0, jst) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := newStepClock(at(15, 7).Add(30 * time.Second))
+	ticks := util.AlignedTickerIn(ctx, jst, 15*time.Minute, 0, clock)
+	got, err := util.RecvN(ctx, ticks, 3)
+	a.NoError(err)
+	a.Equal([]time.Time{at(15, 15), at(15, 30), at(15, 45)}, got)
+
+	cancel()
+	_, err = util.DrainContext(context.Background(), ticks)
+	a.NoError(err)
+}
+
+func TestAlignedTickerSleep(t *testing.T) {
+	a := assert.New(t)
+
+	jst := date(2025, 8, 8).Location()
+	at := func(hour, min int) time.Time { return time.Date(2025, 8, 8, hour, min, 0, 0, jst) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := &jumpClock{stepClock: newStepClock(at(15, 7)), jump: 2*time.Hour + 20*time.Minute}
+	ticks := util.AlignedTickerIn(ctx, jst, 15*time.Minute, 0, clock)
+	got, err := util.RecvN(ctx, ticks, 2)
+	a.NoError(err)
+	a.Equal([]time.Time{at(17, 15), at(17, 30)}, got, "missed boundaries are skipped")
+}
+
+func TestAlignedTickerInvalidInterval(t *testing.T) {
+	a := assert.New(t)
+
+	a.Panics(func() { util.AlignedTicker(context.Background(), 0, 0) })
+}
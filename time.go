@@ -144,6 +144,18 @@ func Past(t time.Time) bool {
 	return t.Before(time.Now())
 }
 
+// FutureWith reports whether t is after c.Now().
+// It is the Clocker variant of Future.
+func FutureWith(c Clocker, t time.Time) bool {
+	return t.After(c.Now())
+}
+
+// PastWith reports whether t is before c.Now().
+// It is the Clocker variant of Past.
+func PastWith(c Clocker, t time.Time) bool {
+	return t.Before(c.Now())
+}
+
 const (
 	HalfHour   = 30 * time.Minute
 	HalfSecond = 500 * time.Millisecond
@@ -191,3 +203,37 @@ func TimeNear(t time.Time, d time.Duration) time.Duration {
 func TimeNearLocal(t time.Time, d time.Duration) time.Duration {
 	return (TimeModLocal(t.Add(d/2), d) - d/2).Abs()
 }
+
+// NextBoundary returns the first wall-clock boundary after t in t's location.
+// Boundaries are the local times that are a multiple of interval plus offset, counted from local midnight
+// for intervals that divide a day. Because it works on wall-clock time, boundaries stay on the same local
+// times across DST shifts: boundaries skipped by a spring-forward shift fall on the first time after it,
+// and boundaries in the hour repeated by a fall-back shift occur on both passes through it.
+// It panics if interval is not positive.
+//
+// For example:
+//
+//	NextBoundary(2025-08-08 15:07:00 JST, 15*time.Minute, 0) = 2025-08-08 15:15:00 JST
+//	NextBoundary(2025-08-08 15:07:00 JST, time.Hour, 5*time.Minute) = 2025-08-08 16:05:00 JST
+func NextBoundary(t time.Time, interval, offset time.Duration) time.Time {
+	if interval <= 0 {
+		panic("non-positive interval for NextBoundary")
+	}
+	loc := t.Location()
+	// Walk the zone offsets in effect from t on, finding the first boundary within each.
+	from, after := t, true
+	for {
+		_, zoneOffset := from.Zone()
+		_, end := from.ZoneBounds()
+		wall := time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), time.UTC)
+		next := wall.Add(-offset).Truncate(interval).Add(offset)
+		if after || next.Before(wall) {
+			next = next.Add(interval)
+		}
+		b := next.Add(-time.Duration(zoneOffset) * time.Second).In(loc)
+		if end.IsZero() || b.Before(end) {
+			return b
+		}
+		from, after = end, false
+	}
+}
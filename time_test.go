@@ -159,3 +159,48 @@ func TestFuturePast(t *testing.T) {
 	a.False(util.Past(futureTime))
 	a.True(util.Past(pastTime))
 }
+
+func TestNextBoundary(t *testing.T) {
+	a := assert.New(t)
+
+	jst := date(2025, 8, 8).Location()
+	at := func(hour, min, sec int) time.Time { return time.Date(2025, 8, 8, hour, min, sec, 0, jst) }
+
+	a.Equal(at(15, 15, 0), util.NextBoundary(at(15, 7, 0), 15*time.Minute, 0))
+	a.Equal(at(15, 30, 0), util.NextBoundary(at(15, 15, 0), 15*time.Minute, 0), "a boundary is not after itself")
+	a.Equal(at(16, 5, 0), util.NextBoundary(at(15, 7, 0), time.Hour, 5*time.Minute))
+	a.Equal(at(9, 0, 0).AddDate(0, 0, 1), util.NextBoundary(at(9, 0, 0), 24*time.Hour, 9*time.Hour))
+	a.Equal(date(2025, 8, 9), util.NextBoundary(at(8, 0, 0), 24*time.Hour, 0), "local midnight, before the UTC offset hour")
+
+	ny, err := time.LoadLocation("America/New_York")
+	a.NoError(err)
+	// 2025-03-09 02:00 EST jumps to 03:00 EDT.
+	before := time.Date(2025, 3, 9, 1, 50, 0, 0, ny)
+	a.Equal(time.Date(2025, 3, 9, 3, 0, 0, 0, ny), util.NextBoundary(before, time.Hour, 0))
+	// The daily boundary stays on local midnight across the shift.
+	a.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, ny), util.NextBoundary(before, 24*time.Hour, 0))
+
+	// 2025-11-02 02:00 EDT falls back to 01:00 EST, repeating 01:00-02:00.
+	firstPass := time.Date(2025, 11, 2, 1, 50, 0, 0, ny) // EDT
+	repeated := firstPass.Add(10 * time.Minute)          // 01:00 EST
+	a.Equal(repeated, util.NextBoundary(firstPass, 15*time.Minute, 0), "the repeated hour is ticked through again")
+	a.Equal(repeated.Add(15*time.Minute), util.NextBoundary(repeated.Add(5*time.Minute), 15*time.Minute, 0))
+	a.Equal(repeated.Add(time.Hour), util.NextBoundary(firstPass.Add(time.Hour), 15*time.Minute, 0))
+	a.Equal(time.Date(2025, 11, 3, 0, 0, 0, 0, ny), util.NextBoundary(firstPass, 24*time.Hour, 0))
+
+	a.Panics(func() { util.NextBoundary(before, 0, 0) })
+	a.Panics(func() { util.NextBoundary(before, -time.Hour, 0) })
+}
+
+func TestFuturePastWith(t *testing.T) {
+	a := assert.New(t)
+
+	now := date(2025, 8, 8)
+	clock := util.NewFakeClock(now)
+	a.True(util.FutureWith(clock, now.Add(time.Minute)))
+	a.False(util.PastWith(clock, now.Add(time.Minute)))
+
+	clock.Advance(2 * time.Minute)
+	a.False(util.FutureWith(clock, now.Add(time.Minute)))
+	a.True(util.PastWith(clock, now.Add(time.Minute)))
+}
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldError is an error for the value at a field path, such as "items[3].price".
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON renders the error as {"path": ..., "message": ...}.
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path    string `json:"path"`
+		Message string `json:"message"`
+	}{e.Path, e.Err.Error()})
+}
+
+// ErrorList is a list of field errors. It renders one error per line,
+// and unwraps to its elements so that errors.Is and ErrorsAs see each of them.
+type ErrorList []*FieldError
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, e := range l {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Err returns l as an error, or nil if l is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// JoinFieldPath joins a field path prefix and a path.
+//
+// For example:
+//
+//	JoinFieldPath("items", "price") = "items.price"
+//	JoinFieldPath("items", "[3]") = "items[3]"
+//	JoinFieldPath("", "price") = "price"
+func JoinFieldPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case strings.HasPrefix(path, "["):
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
+
+// Validator collects field errors instead of stopping at the first one.
+// Validators returned by At and Index share the errors of their parent and prefix the paths they add.
+// The zero value is not usable; use NewValidator.
+type Validator struct {
+	prefix string
+	errs   *ErrorList
+}
+
+// NewValidator returns an empty Validator.
+func NewValidator() *Validator {
+	return &Validator{errs: &ErrorList{}}
+}
+
+// At returns a Validator that adds errors under path, e.g. v.At("address").Add("zip", err) adds "address.zip".
+func (v *Validator) At(path string) *Validator {
+	return &Validator{prefix: JoinFieldPath(v.prefix, path), errs: v.errs}
+}
+
+// Index returns a Validator that adds errors under the element i, e.g. v.At("items").Index(3) adds "items[3]...".
+func (v *Validator) Index(i int) *Validator {
+	return v.At("[" + strconv.Itoa(i) + "]")
+}
+
+// Add records err for path. It does nothing if err is nil.
+// If err is an ErrorList, such as one returned by a nested Validator, its errors are added
+// with their paths prefixed by path. Any other error, including one wrapping or joining an ErrorList,
+// is recorded as a single FieldError so that nothing in it is lost.
+func (v *Validator) Add(path string, err error) {
+	if err == nil {
+		return
+	}
+	path = JoinFieldPath(v.prefix, path)
+	if list, ok := err.(ErrorList); ok {
+		for _, e := range list {
+			*v.errs = append(*v.errs, &FieldError{Path: JoinFieldPath(path, e.Path), Err: e.Err})
+		}
+		return
+	}
+	*v.errs = append(*v.errs, &FieldError{Path: path, Err: err})
+}
+
+// Addf records an error for path with a message formatted by fmt.Errorf.
+func (v *Validator) Addf(path string, format string, args ...any) {
+	v.Add(path, fmt.Errorf(format, args...))
+}
+
+// Check records an error with message for path if ok is false, and returns ok.
+func (v *Validator) Check(ok bool, path string, message string) bool {
+	if !ok {
+		v.Add(path, errors.New(message))
+	}
+	return ok
+}
+
+// Errors returns the errors collected so far, by every Validator sharing them.
+func (v *Validator) Errors() ErrorList {
+	return *v.errs
+}
+
+// Err returns the collected errors as an ErrorList, or nil if there are none.
+func (v *Validator) Err() error {
+	return v.Errors().Err()
+}
@@ -0,0 +1,95 @@
+package util_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/naycoma/util"
+)
+
+func TestJoinFieldPath(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("items.price", util.JoinFieldPath("items", "price"))
+	a.Equal("items[3]", util.JoinFieldPath("items", "[3]"))
+	a.Equal("price", util.JoinFieldPath("", "price"))
+	a.Equal("items", util.JoinFieldPath("items", ""))
+}
+
+func TestValidator(t *testing.T) {
+	a := assert.New(t)
+
+	v := util.NewValidator()
+	a.NoError(v.Err())
+
+	errRequired := errors.New("required")
+	v.Add("name", errRequired)
+	v.Add("email", nil)
+	a.True(v.Check(true, "age", "must be positive"))
+	a.False(v.Check(false, "age", "must be positive"))
+	items := v.At("items")
+	for i, price := range []int{10, -1, 5, -3} {
+		item := items.Index(i)
+		if price < 0 {
+			item.Addf("price", "must not be negative, got %d", price)
+		}
+	}
+	v.At("address").Add("zip", errRequired)
+
+	err := v.Err()
+	a.EqualError(err, `name: required
+age: must be positive
+items[1].price: must not be negative, got -1
+items[3].price: must not be negative, got -3
+address.zip: required`)
+	a.ErrorIs(err, errRequired)
+	a.Len(util.ErrorsAs[*util.FieldError](err), 5)
+	first, ok := util.ErrorAs[*util.FieldError](err)
+	a.True(ok)
+	a.Equal("name", first.Path)
+
+	joined := errors.Join(errors.New("other"), err)
+	a.Len(util.ErrorsAs[*util.FieldError](joined), 5)
+}
+
+func TestValidatorNested(t *testing.T) {
+	a := assert.New(t)
+
+	validateAddress := func() error {
+		v := util.NewValidator()
+		v.Check(false, "zip", "required")
+		v.Check(false, "city", "required")
+		return v.Err()
+	}
+
+	v := util.NewValidator()
+	v.At("shipping").Add("", validateAddress())
+	v.Add("billing", validateAddress())
+	a.Equal([]string{"shipping.zip", "shipping.city", "billing.zip", "billing.city"},
+		[]string{v.Errors()[0].Path, v.Errors()[1].Path, v.Errors()[2].Path, v.Errors()[3].Path})
+
+	// Only a bare ErrorList is flattened; wrapped or joined lists are kept whole.
+	v = util.NewValidator()
+	v.Add("addr", errors.Join(errors.New("db lookup failed"), validateAddress()))
+	v.Add("home", fmt.Errorf("lookup: %w", validateAddress()))
+	a.Len(v.Errors(), 2)
+	a.EqualError(v.Err(), `addr: db lookup failed
+zip: required
+city: required
+home: lookup: zip: required
+city: required`)
+}
+
+func TestErrorListJSON(t *testing.T) {
+	a := assert.New(t)
+
+	v := util.NewValidator()
+	v.At("items").Index(0).Add("qty", errors.New("too large"))
+	b, err := json.Marshal(v.Errors())
+	a.NoError(err)
+	a.JSONEq(`[{"path": "items[0].qty", "message": "too large"}]`, string(b))
+}